@@ -0,0 +1,190 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/limaJavier/timetabling/pkg/model"
+	"github.com/samber/lo"
+)
+
+type lessonJson struct {
+	Period    uint64   `json:"period"`
+	Week      uint64   `json:"week,omitempty"`
+	Day       uint64   `json:"day"`
+	Subject   uint64   `json:"subject"`
+	Professor uint64   `json:"professor"`
+	Classes   []uint64 `json:"classes"`
+	Room      uint64   `json:"room"`
+}
+
+type moveJson struct {
+	From lessonJson `json:"from"`
+	To   lessonJson `json:"to"`
+}
+
+type diffJson struct {
+	Added       []lessonJson      `json:"added"`
+	Removed     []lessonJson      `json:"removed"`
+	Moved       []moveJson        `json:"moved"`
+	RoomChanges []moveJson        `json:"roomChanges"`
+	Classes     map[string]uint64 `json:"classes"`
+	Professors  map[string]uint64 `json:"professors"`
+}
+
+// Compares two timetables written by the CLI (i.e. "timetabler diff -file input.json -old old.json -new new.json"),
+// where the previous timetable may have been built for another version of the input (i.e. "-oldfile old-input.json -newfile input.json")
+func runDiff(arguments []string) {
+	// Define arguments
+	flags := flag.NewFlagSet("diff", flag.ExitOnError)
+	filePathPtr := flags.String("file", "", "Path to the input file both timetables refer to")
+	oldInputFilePathPtr := flags.String("oldfile", "", "Path to the input file the previous timetable refers to, if it differs from the current one")
+	newInputFilePathPtr := flags.String("newfile", "", "Path to the input file the current timetable refers to, if it differs from the previous one")
+	oldFilePathPtr := flags.String("old", "", "Path to the previous timetable")
+	newFilePathPtr := flags.String("new", "", "Path to the current timetable")
+	formatPtr := flags.String("format", "text", "Output format. Allowed values are: \"text\", \"json\", where \"text\" is the default")
+	flags.Parse(arguments)
+	oldInputFilePath := lo.Ternary(*oldInputFilePathPtr != "", *oldInputFilePathPtr, *filePathPtr)
+	newInputFilePath := lo.Ternary(*newInputFilePathPtr != "", *newInputFilePathPtr, *filePathPtr)
+	format := strings.ToLower(*formatPtr)
+
+	// Validate arguments
+	if oldInputFilePath == "" || newInputFilePath == "" || *oldFilePathPtr == "" || *newFilePathPtr == "" {
+		log.Fatal("the input files and both timetables must be specified")
+	} else if format != "text" && format != "json" {
+		log.Fatalf("%v is not a valid format", format)
+	}
+
+	// Extract inputs and timetables
+	previousInput, err := model.InputFromJson(oldInputFilePath)
+	if err != nil {
+		log.Fatalf("cannot parse previous input file: %v", err)
+	}
+	input, err := model.InputFromJson(newInputFilePath)
+	if err != nil {
+		log.Fatalf("cannot parse current input file: %v", err)
+	}
+	previous, vanished, err := readTimetable(*oldFilePathPtr, input) // Lessons are matched by Id, so the previous timetable is read against the current input
+	if err != nil {
+		log.Fatalf("cannot parse previous timetable: %v", err)
+	}
+	current, unknown, err := readTimetable(*newFilePathPtr, input)
+	if err != nil {
+		log.Fatalf("cannot parse current timetable: %v", err)
+	} else if len(unknown) > 0 {
+		log.Fatalf("the current timetable does not refer to the current input: %v", describeLesson(input, unknown[0]))
+	}
+
+	diff := model.Diff(previous, current, input)
+	if format == "text" {
+		writeDiff(os.Stdout, input, diff, previousInput, vanished)
+		return
+	}
+
+	diffJson, err := json.Marshal(toDiffJson(input, diff, previousInput, vanished))
+	if err != nil {
+		log.Fatalf("an error occurred while building output json: %v", err)
+	}
+	fmt.Println(string(diffJson))
+}
+
+// Writes a human-readable report of the differences, where the vanished lessons (i.e. those of entries missing from the current input) are reported as removed
+func writeDiff(writer io.Writer, input model.ModelInput, diff model.TimetableDiff, previousInput model.ModelInput, vanished []lessonJson) {
+	fmt.Fprintf(writer, "Added lessons: %v\n", len(diff.Added))
+	for _, positive := range diff.Added {
+		fmt.Fprintf(writer, "+ %v\n", describe(input, positive))
+	}
+	fmt.Fprintf(writer, "Removed lessons: %v\n", len(diff.Removed)+len(vanished))
+	for _, positive := range diff.Removed {
+		fmt.Fprintf(writer, "- %v\n", describe(input, positive))
+	}
+	for _, lesson := range vanished {
+		fmt.Fprintf(writer, "- %v\n", describeLesson(previousInput, lesson))
+	}
+	fmt.Fprintf(writer, "Moved lessons: %v\n", len(diff.Moved))
+	for _, move := range diff.Moved {
+		fmt.Fprintf(writer, "~ %v -> %v\n", describe(input, move.From), describe(input, move.To))
+	}
+	fmt.Fprintf(writer, "Room changes: %v\n", len(diff.RoomChanges))
+	for _, move := range diff.RoomChanges {
+		fmt.Fprintf(writer, "~ %v -> %v\n", describe(input, move.From), input.Rooms[move.To[5]].Name)
+	}
+
+	classes, professors := changesPerName(input, diff, previousInput, vanished)
+	fmt.Fprintln(writer, "Changes per class:")
+	for _, class := range slices.Sorted(maps.Keys(classes)) {
+		fmt.Fprintf(writer, "  %v: %v\n", class, classes[class])
+	}
+	fmt.Fprintln(writer, "Changes per professor:")
+	for _, professor := range slices.Sorted(maps.Keys(professors)) {
+		fmt.Fprintf(writer, "  %v: %v\n", professor, professors[professor])
+	}
+}
+
+// Returns the number of changes per class and per professor name, counting the vanished lessons along with the differences
+func changesPerName(input model.ModelInput, diff model.TimetableDiff, previousInput model.ModelInput, vanished []lessonJson) (classes, professors map[string]uint64) {
+	classes = make(map[string]uint64)
+	professors = make(map[string]uint64)
+	for class, changes := range diff.Classes {
+		classes[input.Classes[class].Name] += changes
+	}
+	for professor, changes := range diff.Professors {
+		professors[input.Professors[professor].Name] += changes
+	}
+	for _, lesson := range vanished {
+		for _, class := range lesson.Classes {
+			classes[nameById(previousInput.Classes, class, func(class model.Class) (uint64, string) { return class.Id, class.Name })]++
+		}
+		professors[nameById(previousInput.Professors, lesson.Professor, func(professor model.Professor) (uint64, string) { return professor.Id, professor.Name })]++
+	}
+	return classes, professors
+}
+
+func toDiffJson(input model.ModelInput, diff model.TimetableDiff, previousInput model.ModelInput, vanished []lessonJson) diffJson {
+	toLessons := func(positives [][6]uint64) []lessonJson {
+		lessons := make([]lessonJson, 0, len(positives))
+		for _, positive := range positives {
+			lessons = append(lessons, toLessonJson(input, positive))
+		}
+		return lessons
+	}
+	toMoves := func(moves []model.Move) []moveJson {
+		movesJson := make([]moveJson, 0, len(moves))
+		for _, move := range moves {
+			movesJson = append(movesJson, moveJson{From: toLessonJson(input, move.From), To: toLessonJson(input, move.To)})
+		}
+		return movesJson
+	}
+
+	classes, professors := changesPerName(input, diff, previousInput, vanished)
+	return diffJson{
+		Added:       toLessons(diff.Added),
+		Removed:     append(toLessons(diff.Removed), vanished...), // Vanished lessons are already identified by Ids
+		Moved:       toMoves(diff.Moved),
+		RoomChanges: toMoves(diff.RoomChanges),
+		Classes:     classes,
+		Professors:  professors,
+	}
+}
+
+// Converts the positive into a lesson identified by the Ids of its subject, professor, classes and room, as timetables are written by the CLI
+func toLessonJson(input model.ModelInput, positive [6]uint64) lessonJson {
+	subjectProfessor := input.SubjectProfessors[positive[3]]
+	week, day := model.WeekDay(input, positive[1])
+	return lessonJson{
+		Period:    positive[0],
+		Week:      week,
+		Day:       day,
+		Subject:   input.Subjects[subjectProfessor.Subject].Id,
+		Professor: input.Professors[subjectProfessor.Professor].Id,
+		Classes:   lo.Map(input.Groups[positive[4]].Classes, func(class uint64, _ int) uint64 { return input.Classes[class].Id }),
+		Room:      input.Rooms[positive[5]].Id,
+	}
+}
@@ -26,10 +26,11 @@ var Days = map[uint64]string{
 }
 
 var (
-	roomSimilarity  float32
-	validStrategies = []string{"pure", "postponed", "hybrid"}
-	validSolvers    = []string{"kissat", "cadical", "minisat", "cryptominisat", "glucosesimp", "glucosesyrup", "slime", "ortoolsat"}
-	timetablers     = map[string]func(sat.SATSolver) model.Timetabler{
+	roomSimilarity     float32
+	validStrategies    = []string{"pure", "postponed", "hybrid", "optimal", "iterative"}
+	validSolvers       = []string{"kissat", "cadical", "minisat", "cryptominisat", "glucosesimp", "glucosesyrup", "slime", "ortoolsat"}
+	validMaxSATSolvers = []string{"rc2", "openwbo"}
+	timetablers        = map[string]func(sat.SATSolver) model.Timetabler{
 		"pure": model.NewEmbeddedRoomTimetabler,
 		"postponed": func(solver sat.SATSolver) model.Timetabler {
 			return model.NewIsolatedRoomTimetabler(solver, false, 0)
@@ -48,34 +49,114 @@ var (
 		"slime":         sat.NewSlimeSolver,
 		"ortoolsat":     sat.NewOrtoolsatSolver,
 	}
+	objectives = map[string]model.Objective{
+		"gaps":     model.ObjectiveGaps,
+		"disliked": model.ObjectiveDislikedSlots,
+		"late":     model.ObjectiveLatePeriods,
+	}
+	searches = map[string]model.Search{
+		"linear": model.LinearSearch,
+		"binary": model.BinarySearch,
+	}
+	maxSATSolvers = map[string]func() sat.MaxSATSolver{
+		"rc2":     sat.NewRC2Solver,
+		"openwbo": sat.NewOpenWBOSolver,
+	}
+	partitions = map[string]func(stages []string) model.Partition{
+		"prefix": model.PartitionByClassPrefix,
+		"tag":    model.PartitionByTag,
+	}
 )
 
 func main() {
+	// Delegate to the diff command (i.e. "timetabler diff ...")
+	if len(os.Args) > 1 && os.Args[1] == "diff" {
+		runDiff(os.Args[2:])
+		return
+	}
+
 	setConfigPath()
 	// Define arguments
 	strategyPtr := flag.String("strategy", "pure", `Strategy to build the timetable. Allowed values are: 
 - "pure" (All restrictions and assigments are guarenteed by the SAT, therefore a solution will be found if it exists), 
 - "postponed"(Room assigment will be postponed. Correctness is not guaranteed) and 
-- "hybrid"(Room assignment is postponed, but similarity restriction are imposed in the SAT. Correctness is not guaranteed), 
+- "hybrid"(Room assignment is postponed, but similarity restriction are imposed in the SAT. Correctness is not guaranteed) and
+- "optimal"(Like "pure", but the soft constraints' cost is minimized by a MaxSAT-Solver) and
+- "iterative"(Like "pure", but the objective is minimized by repeatedly calling the SAT-Solver with a tightening bound), 
 where \"pure\" is the default`)
 	solverPtr := flag.String("solver", "kissat", "SAT-Solver to use. Allowed values are: \"kissat\", \"cadical\", \"minisat\", \"cryptominisat\", \"glucosesimp\", \"glucosesyrup\", \"slime\", \"ortoolsat\", where \"kissat\" is the default")
+	maxSATSolverPtr := flag.String("maxsolver", "rc2", "MaxSAT-Solver used by the optimal strategy. Allowed values are: \"rc2\", \"openwbo\", where \"rc2\" is the default")
+	objectivePtr := flag.String("objective", "gaps", "Objective minimized by the iterative strategy. Allowed values are: \"gaps\", \"disliked\", \"late\", where \"gaps\" is the default")
+	searchPtr := flag.String("search", "linear", "Search used by the iterative strategy to tighten the objective's bound. Allowed values are: \"linear\", \"binary\", where \"linear\" is the default")
+	budgetPtr := flag.Duration("budget", 0, "Time budget of the iterative strategy (e.g. \"5m\"), after which the best timetable found so far is returned; 0 (the default) means no limit")
+	localSearchPtr := flag.Duration("localsearch", 0, "Time budget of the local search improving the built timetable according to the input's weights (e.g. \"30s\"); 0 (the default) disables it")
+	seedPtr := flag.Int64("seed", 1, "Seed of the local search and the model counter, where 1 is the default")
 	roomSimilarityPtr := flag.Float64("similarity", 0.5, "Similarity threshold (between 0 and 1) used by the hybrid strategy, where 0.5 is the default")
+	symmetryPtr := flag.Bool("symmetry", false, "Break the symmetries among the lessons of each entry and among interchangeable rooms (only allowed for the \"pure\", \"postponed\" and \"hybrid\" strategies)")
 	filePathPtr := flag.String("file", "", "Path to the input file")
+	solutionsPtr := flag.Uint64("solutions", 1, "Number of distinct timetables to enumerate (only allowed for the \"pure\", \"postponed\" and \"hybrid\" strategies), where 1 is the default")
+	distancePtr := flag.Uint64("distance", 1, "Minimum number of lessons taught at other periods or days between enumerated timetables, where 1 is the default")
+	analyzePtr := flag.Bool("analyze", false, "Report the slots each entry is forced to (or can) take along with the approximate number of timetables, instead of building one (only allowed for the \"pure\", \"postponed\" and \"hybrid\" strategies, and not along with local search)")
+	decomposePtr := flag.Bool("decompose", false, "Split the input into independent components (i.e. sharing no classes, professors nor rooms) and build them in parallel (not allowed along with enumeration, analysis or rebuilding)")
+	stagesPtr := flag.String("stages", "", "Comma-separated stages (e.g. \"CC-1,CC-2\") built one after another, pinning the previous stages' lessons and backtracking when a stage is unsatisfiable (only allowed for the \"pure\" strategy, and not along with enumeration, analysis, decomposition or rebuilding)")
+	stageByPtr := flag.String("stageby", "prefix", "How entries are matched to stages. Allowed values are: \"prefix\" (the stage is a prefix of the name of some class of the entry), \"tag\" (the stage is the entry's tag), where \"prefix\" is the default")
+	backtracksPtr := flag.Uint64("backtracks", 0, "Maximum number of backtracks of the staged building; 0 (the default) means no limit")
+	previousFilePathPtr := flag.String("previous", "", "Path to a timetable previously written by the CLI; if given, it's rebuilt for the input keeping as many of its assignments as possible (only allowed for the \"optimal\" and \"iterative\" strategies)")
 	outFilePathPtr := flag.String("out", "", "Path to the file where the output will be written; if empty, it'll be written into the Standard Output")
 	flag.Parse()
 	strategy := strings.ToLower(*strategyPtr)
 	solverStr := strings.ToLower(*solverPtr)
+	maxSATSolverStr := strings.ToLower(*maxSATSolverPtr)
+	objectiveStr := strings.ToLower(*objectivePtr)
+	searchStr := strings.ToLower(*searchPtr)
 	roomSimilarity = float32(*roomSimilarityPtr)
 	filePath := *filePathPtr
+	previousFilePath := *previousFilePathPtr
+	solutions := *solutionsPtr
 	outFile := *outFilePathPtr
+	stages := lo.Filter(lo.Map(strings.Split(*stagesPtr, ","), func(stage string, _ int) string { return strings.TrimSpace(stage) }), func(stage string, _ int) bool {
+		return stage != "" // Empty stages (e.g. left by a trailing comma) would match every entry by prefix
+	})
 
 	// Validate arguments
 	if !slices.Contains(validStrategies, strategy) {
 		log.Fatalf("%v is not a valid strategy", strategy)
 	} else if !slices.Contains(validSolvers, solverStr) {
 		log.Fatalf("%v is not a valid solver", solverStr)
+	} else if !slices.Contains(validMaxSATSolvers, maxSATSolverStr) {
+		log.Fatalf("%v is not a valid MaxSAT solver", maxSATSolverStr)
+	} else if _, ok := objectives[objectiveStr]; !ok {
+		log.Fatalf("%v is not a valid objective", objectiveStr)
+	} else if _, ok := searches[searchStr]; !ok {
+		log.Fatalf("%v is not a valid search", searchStr)
 	} else if filePath == "" {
 		log.Fatal("an input file must be specified")
+	} else if previousFilePath != "" && strategy != "optimal" && strategy != "iterative" {
+		log.Fatalf("a timetable cannot be rebuilt with the %v strategy", strategy)
+	} else if solutions == 0 {
+		log.Fatal("at least one solution must be requested")
+	} else if solutions > 1 && (strategy == "optimal" || strategy == "iterative") {
+		log.Fatalf("timetables cannot be enumerated with the %v strategy", strategy)
+	} else if solutions > 1 && (previousFilePath != "" || *localSearchPtr > 0) {
+		log.Fatal("enumeration cannot be combined with local search or rebuilding")
+	} else if *analyzePtr && (strategy == "optimal" || strategy == "iterative") {
+		log.Fatalf("instances cannot be analyzed with the %v strategy", strategy)
+	} else if *analyzePtr && *localSearchPtr > 0 {
+		log.Fatal("analysis cannot be combined with local search")
+	} else if *decomposePtr && (solutions > 1 || *analyzePtr || previousFilePath != "") {
+		log.Fatal("decomposition cannot be combined with enumeration, analysis or rebuilding")
+	} else if *symmetryPtr && (strategy == "optimal" || strategy == "iterative") {
+		log.Fatalf("symmetries cannot be broken with the %v strategy", strategy)
+	} else if *stagesPtr != "" && strategy != "pure" { // Other strategies either cannot be staged or assign rooms after solving, which backtracking never retries
+		log.Fatalf("timetables cannot be built in stages with the %v strategy", strategy)
+	} else if *stagesPtr != "" && (solutions > 1 || *analyzePtr || *decomposePtr || previousFilePath != "") {
+		log.Fatal("staged building cannot be combined with enumeration, analysis, decomposition or rebuilding")
+	} else if *stagesPtr != "" && len(stages) == 0 {
+		log.Fatalf("%q holds no stages", *stagesPtr)
+	} else if _, ok := partitions[strings.ToLower(*stageByPtr)]; !ok {
+		log.Fatalf("%v is not a valid stage matching", *stageByPtr)
+	} else if previousFilePath != "" && *localSearchPtr > 0 {
+		log.Fatal("local search cannot be combined with rebuilding")
 	} else if strategy == "hybrid" && (roomSimilarity <= 0 || roomSimilarity >= 1) {
 		log.Fatalf("room-similarity must be greater than 0 and smaller than 1: %v", roomSimilarity)
 	}
@@ -84,30 +165,148 @@ where \"pure\" is the default`)
 	input, err := model.InputFromJson(filePath)
 	if err != nil {
 		log.Fatalf("cannot parse input file: %v", err)
+	} else if len(input.TravelTimes) > 0 && (strategy == "postponed" || strategy == "hybrid") {
+		// Rooms are assigned after solving by these strategies, which ignores whether the next lesson's room can be reached during the break
+		log.Fatalf("travel times cannot be taken into account with the %v strategy", strategy)
 	}
 
 	// Initialize engines
-	solver := solvers[solverStr]()
-	timetabler := timetablers[strategy](solver)
+	var timetabler model.Timetabler
+	if strategy == "optimal" {
+		timetabler = model.NewMaxSATTimetabler(maxSATSolvers[maxSATSolverStr]())
+	} else if strategy == "iterative" {
+		timetabler = model.NewIterativeTimetabler(solvers[solverStr](), objectives[objectiveStr], searches[searchStr], *budgetPtr)
+	} else {
+		solver := solvers[solverStr]()
+		timetabler = timetablers[strategy](solver)
+	}
+	if *symmetryPtr {
+		if timetabler, err = model.WithSymmetryBreaking(timetabler); err != nil {
+			log.Fatalf("cannot break symmetries: %v", err)
+		}
+	}
+	if *decomposePtr {
+		timetabler = model.NewDecomposedTimetabler(timetabler)
+	}
+	if len(stages) > 0 {
+		timetabler = model.NewStagedTimetabler(timetabler, partitions[strings.ToLower(*stageByPtr)](stages), *backtracksPtr)
+	}
+	if *localSearchPtr > 0 {
+		timetabler = model.NewLocalSearchTimetabler(timetabler, *seedPtr, *localSearchPtr)
+	}
 
-	// Build timetable
-	timetable, variables, clauses, err := timetabler.Build(input)
+	// Analyze instance
+	if *analyzePtr {
+		analyze(timetabler, input, *seedPtr)
+	}
+
+	// Build (rebuild or enumerate) timetables
+	var (
+		timetable          [][6]uint64
+		timetables         [][][6]uint64
+		variables, clauses uint64
+		diff               model.TimetableDiff
+		vanished           []lessonJson // Lessons of the previous timetable whose entries were removed from the input, which are lost
+	)
+	if previousFilePath != "" {
+		var previous [][6]uint64
+		if previous, vanished, err = readTimetable(previousFilePath, input); err != nil {
+			log.Fatalf("cannot parse previous timetable: %v", err)
+		}
+		timetable, diff, err = timetabler.(model.Rebuilder).Rebuild(previous, input)
+	} else if solutions > 1 {
+		timetables, err = model.Enumerate(timetabler, input, solutions, *distancePtr)
+	} else {
+		timetable, variables, clauses, err = timetabler.Build(input)
+	}
+	if timetable != nil {
+		timetables = append(timetables, timetable)
+	}
 
 	if err != nil {
 		log.Fatalf("an error occurred during timetable construction: %v", err)
-	} else if timetable == nil {
+	} else if len(timetables) == 0 {
 		fmt.Printf("Variables: %v\n", variables)
 		fmt.Printf("Clauses: %v\n", clauses)
 		os.Exit(20)
 	}
 
-	// Verify timetable correctness
-	if !timetabler.Verify(timetable, input) {
-		fmt.Printf("Variables: %v\n", variables)
-		fmt.Printf("Clauses: %v\n", clauses)
-		os.Exit(15)
+	// Verify timetables correctness
+	for _, timetable := range timetables {
+		if !timetabler.Verify(timetable, input) {
+			fmt.Printf("Variables: %v\n", variables)
+			fmt.Printf("Clauses: %v\n", clauses)
+			os.Exit(15)
+		}
+	}
+
+	// Build output from timetables
+	var output any = perClassTimetable(timetables[0], input)
+	if solutions > 1 {
+		output = lo.Map(timetables, func(timetable [][6]uint64, _ int) map[uint64][]map[string]uint64 {
+			return perClassTimetable(timetable, input)
+		})
+	}
+
+	// Marshal output into json
+	perClassTimetableJson, err := json.Marshal(output)
+	if err != nil {
+		log.Fatalf("an error occurred while building output json: %v", err)
+	}
+
+	// Verify outfile is empty, if so then write the results to the Standard Output
+	if outFile == "" {
+		fmt.Println(string(perClassTimetableJson))
+	} else {
+		err := os.WriteFile(outFile, perClassTimetableJson, 0666)
+		if err != nil {
+			log.Fatalf("an error occurred while writing to the output file: %v", err)
+		}
+	}
+
+	fmt.Printf("Variables: %v\n", variables)
+	fmt.Printf("Clauses: %v\n", clauses)
+	if strategy == "iterative" { // Report the cost of the minimized objective
+		input.Weights = objectives[objectiveStr].Weights()
+	}
+	if solutions > 1 {
+		fmt.Printf("Solutions: %v\n", len(timetables))
+	}
+	for _, timetable := range timetables {
+		fmt.Printf("Cost: %v\n", model.Evaluate(timetable, input))
+		for _, satisfaction := range model.ProfessorSatisfaction(timetable, input) {
+			fmt.Printf("Satisfaction of %v: %v\n", input.Professors[satisfaction.Professor].Name, satisfaction)
+		}
+	}
+	if previousFilePath != "" {
+		writeDiff(os.Stdout, input, diff, input, vanished)
+	}
+	os.Exit(10)
+}
+
+func setConfigPath() {
+	execPath, err := os.Executable()
+	if err != nil {
+		log.Fatalf("cannot determine executable path: %v", err)
+	}
+	execPath = path.Dir(execPath)
+
+	// Verify config.json exists
+	files, err := os.ReadDir(execPath)
+	if err != nil {
+		log.Fatalf("cannot read executable's directory: %v", err)
+	}
+	fileNames := lo.Map(files, func(file os.DirEntry, _ int) string { return file.Name() })
+
+	if !slices.Contains(fileNames, "config.json") {
+		log.Fatalf("config.json file was not found: %v", fileNames)
 	}
 
+	sat.ConfigPath = execPath + "/config.json"
+}
+
+// Builds the output of the timetable, i.e. its lessons (sorted by day and period) per class, identified by Ids
+func perClassTimetable(timetable [][6]uint64, input model.ModelInput) map[uint64][]map[string]uint64 {
 	compare := func(a, b [6]uint64, i int) int {
 		if a[i] < b[i] {
 			return -1
@@ -129,12 +328,12 @@ where \"pure\" is the default`)
 	perClassTimetable := make(map[uint64][]map[string]uint64)
 	for _, positive := range timetable {
 		period := positive[0]
-		day := positive[1]
+		week, day := model.WeekDay(input, positive[1])
 		subjectProfessor := positive[3]
 		subject := input.Subjects[input.SubjectProfessors[subjectProfessor].Subject].Id
 		professor := input.Professors[input.SubjectProfessors[subjectProfessor].Professor].Id
 		group := positive[4]
-		room := positive[5]
+		room := input.Rooms[positive[5]].Id
 
 		// dayName := Days[day]
 		// subjectProfessorName := fmt.Sprintf("%v~%v",
@@ -144,16 +343,21 @@ where \"pure\" is the default`)
 		// roomName := input.Rooms[room].Name
 
 		for _, class := range input.Groups[group].Classes {
+			class := input.Classes[class].Id // Like subjects, professors and rooms, classes are identified by their Ids
 			if _, ok := perClassTimetable[class]; !ok {
 				perClassTimetable[class] = make([]map[string]uint64, 0)
 			}
-			perClassTimetable[class] = append(perClassTimetable[class], map[string]uint64{
+			lesson := map[string]uint64{
 				"period":    period,
 				"day":       day,
 				"subject":   subject,
 				"professor": professor,
 				"room":      room,
-			})
+			}
+			if input.Weeks > 1 {
+				lesson["week"] = week
+			}
+			perClassTimetable[class] = append(perClassTimetable[class], lesson)
 
 			// className := input.Classes[class].Name
 			// if !strings.Contains(className, "cc4") {
@@ -163,44 +367,32 @@ where \"pure\" is the default`)
 		}
 	}
 
-	// Marshal output into json
-	perClassTimetableJson, err := json.Marshal(perClassTimetable)
-	if err != nil {
-		log.Fatalf("an error occurred while building output json: %v", err)
-	}
-
-	// Verify outfile is empty, if so then write the results to the Standard Output
-	if outFile == "" {
-		fmt.Println(string(perClassTimetableJson))
-	} else {
-		err := os.WriteFile(outFile, perClassTimetableJson, 0666)
-		if err != nil {
-			log.Fatalf("an error occurred while writing to the output file: %v", err)
-		}
-	}
-
-	fmt.Printf("Variables: %v\n", variables)
-	fmt.Printf("Clauses: %v\n", clauses)
-	os.Exit(10)
+	return perClassTimetable
 }
 
-func setConfigPath() {
-	execPath, err := os.Executable()
+// Reports the slots each entry is forced to (or can) take along with the approximate number of timetables
+func analyze(timetabler model.Timetabler, input model.ModelInput, seed int64) {
+	analyses, err := model.Backbone(timetabler, input)
 	if err != nil {
-		log.Fatalf("cannot determine executable path: %v", err)
+		log.Fatalf("an error occurred during backbone computation: %v", err)
+	} else if analyses == nil {
+		os.Exit(20)
 	}
-	execPath = path.Dir(execPath)
 
-	// Verify config.json exists
-	files, err := os.ReadDir(execPath)
-	if err != nil {
-		log.Fatalf("cannot read executable's directory: %v", err)
+	for _, analysis := range analyses {
+		slots := lo.Map(analysis.Fixed, func(slot [2]uint64, _ int) string {
+			return fmt.Sprintf("%v period %d", dayName(input, slot[1]), slot[0])
+		})
+		fmt.Printf("%v: %v %v\n", describeEntry(input, analysis.Entry), analysis, slots)
 	}
-	fileNames := lo.Map(files, func(file os.DirEntry, _ int) string { return file.Name() })
 
-	if !slices.Contains(fileNames, "config.json") {
-		log.Fatalf("config.json file was not found: %v", fileNames)
+	count, exact, err := model.CountTimetables(timetabler, input, seed)
+	if err != nil {
+		log.Fatalf("an error occurred during model counting: %v", err)
+	} else if exact {
+		fmt.Printf("Timetables: %v\n", count)
+	} else {
+		fmt.Printf("Timetables: ~%v\n", count)
 	}
-
-	sat.ConfigPath = execPath + "/config.json"
+	os.Exit(10)
 }
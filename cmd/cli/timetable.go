@@ -0,0 +1,156 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/limaJavier/timetabling/pkg/model"
+)
+
+// Reads a timetable written by the CLI (i.e. lessons per class), translating it back into positives of the given input.
+// Subjects, professors, classes and rooms are matched by Id, so the input may have been edited since the timetable was written;
+// lessons the input no longer holds an entry for are returned apart instead of failing
+func readTimetable(file string, input model.ModelInput) ([][6]uint64, []lessonJson, error) {
+	bytes, err := os.ReadFile(file)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var perClassTimetable map[uint64][]map[string]uint64
+	if err := json.Unmarshal(bytes, &perClassTimetable); err != nil {
+		return nil, nil, err
+	}
+
+	subjects := indicesById(input.Subjects, func(subject model.Subject) uint64 { return subject.Id })
+	professors := indicesById(input.Professors, func(professor model.Professor) uint64 { return professor.Id })
+	classes := indicesById(input.Classes, func(class model.Class) uint64 { return class.Id })
+	rooms := indicesById(input.Rooms, func(room model.Room) uint64 { return room.Id })
+
+	timetable := make([][6]uint64, 0)
+	seen := make(map[[5]uint64]bool) // Lessons of groups of several classes are listed once per class
+	vanished := make([]lessonJson, 0)
+	vanishedIndices := make(map[[6]uint64]int) // Like the others, vanished lessons are listed once per class
+	lessons := make(map[[2]uint64]uint64)
+	for _, classId := range slices.Sorted(maps.Keys(perClassTimetable)) { // Classes are visited in order, so that lessons are numbered deterministically
+		for _, lesson := range perClassTimetable[classId] {
+			day := model.Day(input, lesson["week"], lesson["day"]) // The week is omitted by single-week timetables
+			subject, okSubject := subjects[lesson["subject"]]
+			professor, okProfessor := professors[lesson["professor"]]
+			class, okClass := classes[classId]
+			room, okRoom := rooms[lesson["room"]]
+
+			var entryKey [2]uint64
+			ok := okSubject && okProfessor && okClass && okRoom
+			if ok {
+				entryKey, ok = findEntryOfClass(input, subject, professor, class)
+			}
+			if !ok {
+				key := [6]uint64{lesson["period"], lesson["week"], lesson["day"], lesson["subject"], lesson["professor"], lesson["room"]}
+				if index, merged := vanishedIndices[key]; merged {
+					vanished[index].Classes = append(vanished[index].Classes, classId)
+					continue
+				}
+				vanishedIndices[key] = len(vanished)
+				vanished = append(vanished, lessonJson{
+					Period:    lesson["period"],
+					Week:      lesson["week"],
+					Day:       lesson["day"],
+					Subject:   lesson["subject"],
+					Professor: lesson["professor"],
+					Classes:   []uint64{classId},
+					Room:      lesson["room"],
+				})
+				continue
+			}
+
+			key := [5]uint64{lesson["period"], day, entryKey[0], entryKey[1], room}
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			timetable = append(timetable, [6]uint64{lesson["period"], day, lessons[entryKey], entryKey[0], entryKey[1], room})
+			lessons[entryKey]++
+		}
+	}
+	return timetable, vanished, nil
+}
+
+// Maps the Ids of the items to their indices
+func indicesById[T any](items []T, id func(T) uint64) map[uint64]uint64 {
+	indices := make(map[uint64]uint64, len(items))
+	for index, item := range items {
+		indices[id(item)] = uint64(index)
+	}
+	return indices
+}
+
+// Returns the key of the entry teaching the subject by the professor to a group containing the class
+func findEntryOfClass(input model.ModelInput, subject, professor, class uint64) ([2]uint64, bool) {
+	entryKeys := slices.SortedFunc(maps.Keys(input.Entries), func(a, b [2]uint64) int { return slices.Compare(a[:], b[:]) })
+	for _, entryKey := range entryKeys { // Entries are visited in order, so that the same entry is found when several match
+		subjectProfessor := input.SubjectProfessors[entryKey[0]]
+		if subjectProfessor.Subject == subject && subjectProfessor.Professor == professor && slices.Contains(input.Groups[entryKey[1]].Classes, class) {
+			return entryKey, true
+		}
+	}
+	return [2]uint64{}, false
+}
+
+// Returns a human-readable description of the positive (e.g. "Logica~Luciano to [CC-111] on Monday at period 0 in Aula 6")
+func describe(input model.ModelInput, positive [6]uint64) string {
+	return fmt.Sprintf("%v on %v at period %d in %v", describeEntry(input, [2]uint64{positive[3], positive[4]}), dayName(input, positive[1]), positive[0], input.Rooms[positive[5]].Name)
+}
+
+// Returns the name of the day, qualified by its week when the timetable spans several weeks (e.g. "Monday of week 1")
+func dayName(input model.ModelInput, day uint64) string {
+	week, weekDay := model.WeekDay(input, day)
+	if input.Weeks > 1 {
+		return fmt.Sprintf("%v of week %d", Days[weekDay], week)
+	}
+	return Days[weekDay]
+}
+
+// Returns a human-readable description of the entry (e.g. "Logica~Luciano to [CC-111]" or "Logica~Luciano+Dalianys to [CC-111]")
+func describeEntry(input model.ModelInput, entryKey [2]uint64) string {
+	subjectProfessor := input.SubjectProfessors[entryKey[0]]
+	classes := make([]string, 0)
+	for _, class := range input.Groups[entryKey[1]].Classes {
+		classes = append(classes, input.Classes[class].Name)
+	}
+	professors := make([]string, 0)
+	for _, professor := range subjectProfessor.Team() {
+		professors = append(professors, input.Professors[professor].Name)
+	}
+	return fmt.Sprintf("%v~%v to %v", input.Subjects[subjectProfessor.Subject].Name, strings.Join(professors, "+"), classes)
+}
+
+// Returns a human-readable description of a lesson read from a timetable (e.g. "Logica~Luciano to [CC-111] on Monday at period 0 in Aula 6"),
+// naming its subject, professor, classes and room by their Ids within the input
+func describeLesson(input model.ModelInput, lesson lessonJson) string {
+	classes := make([]string, 0, len(lesson.Classes))
+	for _, class := range lesson.Classes {
+		classes = append(classes, nameById(input.Classes, class, func(class model.Class) (uint64, string) { return class.Id, class.Name }))
+	}
+	return fmt.Sprintf("%v~%v to %v on %v at period %d in %v",
+		nameById(input.Subjects, lesson.Subject, func(subject model.Subject) (uint64, string) { return subject.Id, subject.Name }),
+		nameById(input.Professors, lesson.Professor, func(professor model.Professor) (uint64, string) { return professor.Id, professor.Name }),
+		classes,
+		dayName(input, model.Day(input, lesson.Week, lesson.Day)),
+		lesson.Period,
+		nameById(input.Rooms, lesson.Room, func(room model.Room) (uint64, string) { return room.Id, room.Name }),
+	)
+}
+
+// Returns the name of the item with the given Id, or the Id itself (e.g. "#3") when the input holds no such item
+func nameById[T any](items []T, id uint64, identify func(T) (uint64, string)) string {
+	for _, item := range items {
+		if itemId, name := identify(item); itemId == id {
+			return name
+		}
+	}
+	return fmt.Sprintf("#%d", id)
+}
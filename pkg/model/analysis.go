@@ -0,0 +1,225 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"math/rand"
+	"slices"
+)
+
+const (
+	countingThreshold = 32 // Maximum number of solutions enumerated within a cell of the hashed solution space
+	countingTrials    = 5  // Number of independent estimates whose median is returned
+)
+
+// Slots (i.e. periods and days) an entry can be taught at among all timetables of an instance
+type EntryAnalysis struct {
+	Entry        [2]uint64
+	Lessons      uint64
+	Fixed        [][2]uint64 // Periods and days the entry is taught at in every timetable
+	Alternatives [][2]uint64 // Periods and days the entry is taught at in some timetable
+}
+
+// Reports whether every lesson of the entry is forced to a fixed slot or, otherwise, how many slots its lessons can take
+func (analysis EntryAnalysis) String() string {
+	if uint64(len(analysis.Fixed)) == analysis.Lessons {
+		return "fixed slot"
+	} else if len(analysis.Fixed) > 0 {
+		return fmt.Sprintf("%d alternatives (%d fixed)", len(analysis.Alternatives), len(analysis.Fixed))
+	}
+	return fmt.Sprintf("%d alternatives", len(analysis.Alternatives))
+}
+
+// Computes the backbone of the instance's time-slot projection (i.e. the periods and days each entry is taught at in every timetable) along with
+// the slots each entry can take, by means of repeated solver calls where every solution found rules out (flips) as many candidates as possible.
+// Returns nil if the instance is not satisfiable; only the embedded-room and isolated-room timetablers are supported
+func Backbone(timetabler Timetabler, modelInput ModelInput) ([]EntryAnalysis, error) {
+	encoder, ok := timetabler.(encoder)
+	if !ok {
+		return nil, errors.New("the timetabler does not support backbone computation")
+	}
+
+	encoding := encoder.encode(modelInput)
+	projection := encoding.timeProjection()
+	variables := sortedVariables(projection)
+
+	// Solves the instance under the unit clause (if any), returning the projection variables that are true in the solution
+	solve := func(unit []int64) (map[int64]bool, error) {
+		constrained := encoding
+		if unit != nil {
+			constrained.satInstance.Clauses = append(slices.Clone(encoding.satInstance.Clauses), unit)
+		}
+		solution, _, err := constrained.solve(encoder.satSolver())
+		if err != nil || solution == nil {
+			return nil, err
+		}
+
+		trueVariables := make(map[int64]bool)
+		for _, literal := range solution {
+			if _, ok := slices.BinarySearch(variables, literal); ok {
+				trueVariables[literal] = true
+			}
+		}
+		return trueVariables, nil
+	}
+
+	possible, err := solve(nil) // Projection variables that are true in some solution
+	if err != nil || possible == nil {
+		return nil, err
+	}
+	candidates := maps.Clone(possible) // Projection variables that may be true in every solution
+	update := func(trueVariables map[int64]bool) {
+		for variable := range trueVariables {
+			possible[variable] = true
+		}
+		for variable := range candidates {
+			if !trueVariables[variable] {
+				delete(candidates, variable)
+			}
+		}
+	}
+
+	// Find the slots each entry can take
+	for _, variable := range variables {
+		if possible[variable] {
+			continue
+		}
+		trueVariables, err := solve([]int64{variable})
+		if err != nil {
+			return nil, err
+		} else if trueVariables != nil {
+			update(trueVariables)
+		}
+	}
+
+	// Find the slots each entry is forced to take
+	backbone := make(map[int64]bool)
+	for _, variable := range variables {
+		if !candidates[variable] {
+			continue
+		}
+		trueVariables, err := solve([]int64{-variable})
+		if err != nil {
+			return nil, err
+		} else if trueVariables == nil {
+			backbone[variable] = true
+		} else {
+			update(trueVariables)
+		}
+	}
+
+	//** Group slots by entry
+	analyses := make(map[[2]uint64]*EntryAnalysis)
+	for entryKey, entry := range modelInput.Entries {
+		analyses[entryKey] = &EntryAnalysis{Entry: entryKey, Lessons: entry.Lessons, Fixed: make([][2]uint64, 0), Alternatives: make([][2]uint64, 0)}
+	}
+	for key, variable := range projection {
+		analysis, slot := analyses[[2]uint64{key[0], key[1]}], [2]uint64{key[2], key[3]}
+		if possible[variable] {
+			analysis.Alternatives = append(analysis.Alternatives, slot)
+		}
+		if backbone[variable] {
+			analysis.Fixed = append(analysis.Fixed, slot)
+		}
+	}
+
+	result := make([]EntryAnalysis, 0, len(analyses))
+	for _, entryKey := range sortedEntryKeys(modelInput.Entries) {
+		analysis := analyses[entryKey]
+		slices.SortFunc(analysis.Alternatives, compareSlots)
+		slices.SortFunc(analysis.Fixed, compareSlots)
+		result = append(result, *analysis)
+	}
+	return result, nil
+}
+
+// Approximates the number of distinct timetables (i.e. time-slot projections, see Enumerate) by hashing the solution space with random XOR
+// constraints until a cell holds at most countingThreshold of them, returning the median of countingTrials estimates (ApproxMC-like).
+// If the instance has at most countingThreshold timetables, the exact count is returned and exact is true
+func CountTimetables(timetabler Timetabler, modelInput ModelInput, seed int64) (count uint64, exact bool, err error) {
+	encoder, ok := timetabler.(encoder)
+	if !ok {
+		return 0, false, errors.New("the timetabler does not support counting")
+	}
+
+	base := encoder.encode(modelInput)
+	variables := sortedVariables(base.timeProjection())
+	random := rand.New(rand.NewSource(seed))
+
+	// Counts the solutions of the instance constrained by the given XOR constraints, up to countingThreshold+1 of them
+	countCell := func(xors int) (uint64, error) {
+		cell := base
+		cell.satInstance.Clauses = slices.Clone(base.satInstance.Clauses)
+		for range xors {
+			subset := make([]int64, 0)
+			for _, variable := range variables {
+				if random.Intn(2) == 1 {
+					subset = append(subset, variable)
+				}
+			}
+			cell.satInstance.Clauses = append(cell.satInstance.Clauses, xor(subset, random.Intn(2) == 1, base.state.allocator)...)
+		}
+		cell.satInstance.Variables = base.state.allocator.Last()
+
+		return cell.enumerate(encoder.satSolver(), variables, countingThreshold+1, 1, func([][6]uint64) {})
+	}
+
+	// Count exactly if there are few timetables
+	if count, err = countCell(0); err != nil || count <= countingThreshold {
+		return count, err == nil, err
+	}
+
+	estimates := make([]uint64, 0, countingTrials)
+	start := 1 // Trials start right below the number of XOR constraints that sufficed for the previous one
+	for range countingTrials {
+		for xors := start; xors <= len(variables); xors++ {
+			cellCount, err := countCell(xors)
+			if err != nil {
+				return 0, false, err
+			} else if cellCount <= countingThreshold {
+				estimates = append(estimates, cellCount<<xors)
+				start = max(1, xors-1)
+				break
+			}
+		}
+	}
+	if len(estimates) == 0 {
+		return 0, false, nil
+	}
+
+	slices.Sort(estimates)
+	return estimates[len(estimates)/2], false, nil
+}
+
+// Returns the clauses stating that the exclusive disjunction of the literals equals parity, chaining Tseitin-encoded binary XORs
+func xor(literals []int64, parity bool, allocator *variableAllocator) [][]int64 {
+	if len(literals) == 0 {
+		if parity {
+			return [][]int64{{}} // Unsatisfiable
+		}
+		return [][]int64{}
+	}
+
+	clauses := make([][]int64, 0, 4*len(literals))
+	accumulated := literals[0]
+	for _, literal := range literals[1:] {
+		next := allocator.Next() // next = accumulated XOR literal
+		clauses = append(clauses,
+			[]int64{-next, accumulated, literal},
+			[]int64{-next, -accumulated, -literal},
+			[]int64{next, -accumulated, literal},
+			[]int64{next, accumulated, -literal},
+		)
+		accumulated = next
+	}
+
+	if parity {
+		return append(clauses, []int64{accumulated})
+	}
+	return append(clauses, []int64{-accumulated})
+}
+
+func compareSlots(a, b [2]uint64) int {
+	return slices.Compare([]uint64{a[1], a[0]}, []uint64{b[1], b[0]})
+}
@@ -0,0 +1,115 @@
+package model
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestXor(t *testing.T) {
+	for n := uint64(0); n <= 5; n++ {
+		for _, parity := range []bool{false, true} {
+			// Arrange
+			allocator := newVariableAllocator(n)
+			literals := make([]int64, n)
+			for i := range literals {
+				literals[i] = int64(i + 1)
+			}
+
+			// Act
+			clauses := xor(literals, parity, allocator)
+
+			// Assert
+			for assignment := range uint64(1) << n {
+				odd, fixed := false, make(map[int64]bool)
+				for i, literal := range literals {
+					value := assignment&(1<<i) != 0
+					fixed[literal] = value
+					odd = odd != value
+				}
+				assert.Equal(t, odd == parity, satisfiable(clauses, fixed), "n = %d, parity = %v, assignment = %b", n, parity, assignment)
+			}
+		}
+	}
+}
+
+func TestBackbone(t *testing.T) {
+	// Arrange
+	input := twoTimetablesInput(t)
+	timetabler := NewEmbeddedRoomTimetabler(dpllSolver{})
+
+	// Act
+	analyses, err := Backbone(timetabler, input)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, []EntryAnalysis{
+		{Entry: [2]uint64{0, 0}, Lessons: 1, Fixed: [][2]uint64{}, Alternatives: [][2]uint64{{0, 0}, {1, 0}}},
+		{Entry: [2]uint64{1, 1}, Lessons: 1, Fixed: [][2]uint64{}, Alternatives: [][2]uint64{{0, 0}, {1, 0}}},
+		{Entry: [2]uint64{2, 2}, Lessons: 1, Fixed: [][2]uint64{{1, 0}}, Alternatives: [][2]uint64{{1, 0}}},
+	}, analyses)
+}
+
+func TestBackboneUnsatisfiable(t *testing.T) {
+	// Arrange
+	input, err := processRawInput(rawModelInput{ // Luciano cannot teach both Logica and Algebra in a single slot
+		Subjects:   []Subject{{Id: 0, Name: "Logica"}, {Id: 1, Name: "Algebra"}},
+		Professors: []Professor{{Id: 0, Name: "Luciano", Availability: [][]bool{{true}}}},
+		Classes:    []rawClass{{Id: 0, Name: "CC-111", Size: 30}, {Id: 1, Name: "CC-112", Size: 30}},
+		Rooms:      []Room{{Id: 0, Name: "Aula 1", Capacity: 50}},
+		Entries: []rawEntry{
+			{Subject: 0, Professor: 0, Classes: []uint64{0}, Lessons: 1, Permissibility: [][]bool{{true}}, Rooms: []uint64{0}},
+			{Subject: 1, Professor: 0, Classes: []uint64{1}, Lessons: 1, Permissibility: [][]bool{{true}}, Rooms: []uint64{0}},
+		},
+	})
+	assert.Nil(t, err)
+	timetabler := NewEmbeddedRoomTimetabler(dpllSolver{})
+
+	// Act
+	analyses, err := Backbone(timetabler, input)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Nil(t, analyses)
+}
+
+func TestCountTimetables(t *testing.T) {
+	// Independent entries, each with its own professor, class and room, that can be taught at either period, thus there are 2^entries timetables
+	independent := func(entries int) ModelInput {
+		var rawInput rawModelInput
+		for i := range uint64(entries) {
+			rawInput.Subjects = append(rawInput.Subjects, Subject{Id: i, Name: fmt.Sprintf("Asignatura %d", i)})
+			rawInput.Professors = append(rawInput.Professors, Professor{Id: i, Name: fmt.Sprintf("Profesor %d", i), Availability: [][]bool{{true}, {true}}})
+			rawInput.Classes = append(rawInput.Classes, rawClass{Id: i, Name: fmt.Sprintf("CC-%d", 111+i), Size: 30})
+			rawInput.Rooms = append(rawInput.Rooms, Room{Id: i, Name: fmt.Sprintf("Aula %d", i+1), Capacity: 50})
+			rawInput.Entries = append(rawInput.Entries, rawEntry{Subject: i, Professor: i, Classes: []uint64{i}, Lessons: 1, Permissibility: [][]bool{{true}, {true}}, Rooms: []uint64{i}})
+		}
+		input, err := processRawInput(rawInput)
+		assert.Nil(t, err)
+		return input
+	}
+	scenarios := []struct {
+		input    ModelInput
+		exact    bool
+		min, max uint64 // Bounds of the (possibly approximate) count
+	}{
+		{twoTimetablesInput(t), true, 2, 2},
+		{independent(5), true, 32, 32},
+		{independent(7), false, 32, 512}, // 128 timetables
+	}
+
+	for _, scenario := range scenarios {
+		// Arrange
+		timetabler := NewEmbeddedRoomTimetabler(dpllSolver{})
+
+		// Act
+		count, exact, err := CountTimetables(timetabler, scenario.input, 1)
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Equal(t, scenario.exact, exact)
+		assert.GreaterOrEqual(t, count, scenario.min)
+		assert.LessOrEqual(t, count, scenario.max)
+	}
+}
@@ -0,0 +1,142 @@
+package model
+
+import (
+	"slices"
+	"sync/atomic"
+)
+
+// variableAllocator hands out fresh auxiliary variables (i.e. variables that do not represent a scheduling variable), it's safe for concurrent use since constraints are generated on different goroutines
+type variableAllocator struct {
+	last atomic.Uint64
+}
+
+func newVariableAllocator(variables uint64) *variableAllocator {
+	allocator := &variableAllocator{}
+	allocator.last.Store(variables)
+	return allocator
+}
+
+// Returns a fresh auxiliary variable
+func (allocator *variableAllocator) Next() int64 {
+	return int64(allocator.last.Add(1))
+}
+
+// Returns the greatest variable handed out so far (or the number of scheduling variables if none)
+func (allocator *variableAllocator) Last() uint64 {
+	return allocator.last.Load()
+}
+
+// Returns the clauses stating that at most bound of the literals are true, using the sequential counter encoding (Sinz, 2005)
+func atMost(literals []int64, bound uint64, allocator *variableAllocator) [][]int64 {
+	n := uint64(len(literals))
+	clauses := make([][]int64, 0)
+
+	if bound >= n {
+		return clauses
+	} else if bound == 0 {
+		for _, literal := range literals {
+			clauses = append(clauses, []int64{-literal})
+		}
+		return clauses
+	}
+
+	// counters[i][j] is true if at least j+1 of the first i+1 literals are true
+	counters := make([][]int64, n-1)
+	for i := range counters {
+		counters[i] = make([]int64, bound)
+		for j := range counters[i] {
+			counters[i][j] = allocator.Next()
+		}
+	}
+
+	clauses = append(clauses, []int64{-literals[0], counters[0][0]})
+	for j := uint64(1); j < bound; j++ {
+		clauses = append(clauses, []int64{-counters[0][j]})
+	}
+	for i := uint64(1); i < n-1; i++ {
+		clauses = append(clauses,
+			[]int64{-literals[i], counters[i][0]},
+			[]int64{-counters[i-1][0], counters[i][0]},
+		)
+		for j := uint64(1); j < bound; j++ {
+			clauses = append(clauses,
+				[]int64{-literals[i], -counters[i-1][j-1], counters[i][j]},
+				[]int64{-counters[i-1][j], counters[i][j]},
+			)
+		}
+		clauses = append(clauses, []int64{-literals[i], -counters[i-1][bound-1]})
+	}
+	clauses = append(clauses, []int64{-literals[n-1], -counters[n-2][bound-1]})
+
+	return clauses
+}
+
+// Returns the clauses stating that at least bound of the literals are true, using a sequential counter whose registers only imply the counts they represent
+func atLeast(literals []int64, bound uint64, allocator *variableAllocator) [][]int64 {
+	n := uint64(len(literals))
+	clauses := make([][]int64, 0)
+
+	if bound == 0 {
+		return clauses
+	} else if bound > n {
+		return append(clauses, []int64{}) // Unsatisfiable
+	} else if bound == 1 {
+		return append(clauses, slices.Clone(literals))
+	}
+
+	// counters[i][j] is true only if at least j+1 of the first i+1 literals are true
+	counters := make([][]int64, n)
+	for i := range counters {
+		counters[i] = make([]int64, bound)
+		for j := range counters[i] {
+			counters[i][j] = allocator.Next()
+		}
+	}
+
+	clauses = append(clauses, []int64{-counters[0][0], literals[0]})
+	for j := uint64(1); j < bound; j++ {
+		clauses = append(clauses, []int64{-counters[0][j]})
+	}
+	for i := uint64(1); i < n; i++ {
+		clauses = append(clauses, []int64{-counters[i][0], counters[i-1][0], literals[i]})
+		for j := uint64(1); j < bound; j++ {
+			clauses = append(clauses,
+				[]int64{-counters[i][j], counters[i-1][j], literals[i]},
+				[]int64{-counters[i][j], counters[i-1][j], counters[i-1][j-1]},
+			)
+		}
+	}
+	clauses = append(clauses, []int64{counters[n-1][bound-1]})
+
+	return clauses
+}
+
+// Returns the clauses and the output literals of a totalizer (Bailleux and Boufkhad, 2003) over the literals, where outputs[j] is true if at least j+1 of the literals are true. Thus, at most k of the literals are true under the unit clause -outputs[k]
+func totalizer(literals []int64, allocator *variableAllocator) (clauses [][]int64, outputs []int64) {
+	clauses = make([][]int64, 0)
+	if len(literals) <= 1 {
+		return clauses, slices.Clone(literals)
+	}
+
+	leftClauses, left := totalizer(literals[:len(literals)/2], allocator)
+	rightClauses, right := totalizer(literals[len(literals)/2:], allocator)
+	clauses = append(append(clauses, leftClauses...), rightClauses...)
+
+	outputs = make([]int64, len(literals))
+	for i := range outputs {
+		outputs[i] = allocator.Next()
+	}
+
+	// Propagate the counts of both halves along with their sums
+	for i, leftOutput := range left {
+		clauses = append(clauses, []int64{-leftOutput, outputs[i]})
+		for j, rightOutput := range right {
+			clauses = append(clauses, []int64{-leftOutput, -rightOutput, outputs[i+j+1]})
+		}
+	}
+	for j, rightOutput := range right {
+		clauses = append(clauses, []int64{-rightOutput, outputs[j]})
+	}
+
+	return clauses, outputs
+}
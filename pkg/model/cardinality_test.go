@@ -0,0 +1,142 @@
+package model
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAtMost(t *testing.T) {
+	for n := uint64(1); n <= 6; n++ {
+		for bound := uint64(0); bound <= n; bound++ {
+			// Arrange
+			allocator := newVariableAllocator(n)
+			literals := make([]int64, n)
+			for i := range literals {
+				literals[i] = int64(i + 1)
+			}
+
+			// Act
+			clauses := atMost(literals, bound, allocator)
+
+			// Assert
+			for assignment := range uint64(1) << n {
+				trueLiterals, fixed := uint64(0), make(map[int64]bool)
+				for i, literal := range literals {
+					value := assignment&(1<<i) != 0
+					fixed[literal] = value
+					if value {
+						trueLiterals++
+					}
+				}
+				assert.Equal(t, trueLiterals <= bound, satisfiable(clauses, fixed), "n = %d, bound = %d, assignment = %b", n, bound, assignment)
+			}
+		}
+	}
+}
+
+func TestAtLeast(t *testing.T) {
+	for n := uint64(1); n <= 6; n++ {
+		for bound := uint64(0); bound <= n+1; bound++ {
+			// Arrange
+			allocator := newVariableAllocator(n)
+			literals := make([]int64, n)
+			for i := range literals {
+				literals[i] = int64(i + 1)
+			}
+
+			// Act
+			clauses := atLeast(literals, bound, allocator)
+
+			// Assert
+			for assignment := range uint64(1) << n {
+				trueLiterals, fixed := uint64(0), make(map[int64]bool)
+				for i, literal := range literals {
+					value := assignment&(1<<i) != 0
+					fixed[literal] = value
+					if value {
+						trueLiterals++
+					}
+				}
+				assert.Equal(t, trueLiterals >= bound, satisfiable(clauses, fixed), "n = %d, bound = %d, assignment = %b", n, bound, assignment)
+			}
+		}
+	}
+}
+
+func TestTotalizer(t *testing.T) {
+	for n := uint64(1); n <= 6; n++ {
+		// Arrange
+		allocator := newVariableAllocator(n)
+		literals := make([]int64, n)
+		for i := range literals {
+			literals[i] = int64(i + 1)
+		}
+
+		// Act
+		clauses, outputs := totalizer(literals, allocator)
+
+		// Assert
+		assert.Len(t, outputs, int(n))
+		for bound := uint64(0); bound < n; bound++ {
+			bounded := append(slices.Clone(clauses), []int64{-outputs[bound]})
+			for assignment := range uint64(1) << n {
+				trueLiterals, fixed := uint64(0), make(map[int64]bool)
+				for i, literal := range literals {
+					value := assignment&(1<<i) != 0
+					fixed[literal] = value
+					if value {
+						trueLiterals++
+					}
+				}
+				assert.Equal(t, trueLiterals <= bound, satisfiable(bounded, fixed), "n = %d, bound = %d, assignment = %b", n, bound, assignment)
+			}
+		}
+	}
+}
+
+// Checks (by means of a naive DPLL) whether the clauses are satisfiable under the fixed assignment
+func satisfiable(clauses [][]int64, fixed map[int64]bool) bool {
+	value := func(literal int64) (bool, bool) {
+		variable := literal
+		if variable < 0 {
+			variable = -variable
+		}
+		assigned, ok := fixed[variable]
+		return assigned == (literal > 0), ok
+	}
+
+	for _, clause := range clauses {
+		satisfied, unassigned := false, int64(0)
+		for _, literal := range clause {
+			if literalValue, ok := value(literal); !ok {
+				unassigned = literal
+			} else if literalValue {
+				satisfied = true
+				break
+			}
+		}
+		if satisfied {
+			continue
+		} else if unassigned == 0 {
+			return false
+		}
+
+		// Branch on an unassigned variable of the first unsatisfied clause
+		variable := unassigned
+		if variable < 0 {
+			variable = -variable
+		}
+		for _, branch := range []bool{unassigned > 0, unassigned < 0} {
+			fixed[variable] = branch
+			if satisfiable(clauses, fixed) {
+				delete(fixed, variable)
+				return true
+			}
+		}
+		delete(fixed, variable)
+		return false
+	}
+	return true
+}
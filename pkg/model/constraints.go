@@ -1,11 +1,20 @@
 package model
 
-import "math"
+import (
+	"maps"
+	"math"
+	"slices"
+
+	"github.com/samber/lo"
+)
 
 type constraintState struct {
-	evaluator predicateEvaluator
-	indexer   indexer
-	generator permutationGenerator
+	modelInput ModelInput
+	evaluator  predicateEvaluator
+	indexer    indexer
+	generator  permutationGenerator
+	allocator  *variableAllocator
+	choices    map[[2]uint64]int64 // Professor-choice variable of each candidate entry (see ModelInput.Pools)
 
 	periods,
 	days,
@@ -15,6 +24,11 @@ type constraintState struct {
 	rooms uint64
 }
 
+// Returns the number of scheduling variables, which precede every auxiliary one
+func (state constraintState) schedulingVariables() uint64 {
+	return state.periods * state.days * state.lessons * state.subjectProfessors * state.groups * state.rooms
+}
+
 func professorConstraints(state constraintState) [][]int64 {
 	permutations := state.generator.ConstrainedPermutations([]func(permutation []uint64) bool{
 		// A_k(i,j) = 1
@@ -319,6 +333,56 @@ func professorAvailabilityConstraints(state constraintState) [][]int64 {
 	return clauses
 }
 
+func roomAvailabilityConstraints(state constraintState) [][]int64 {
+	permutations := state.generator.ConstrainedPermutations([]func(permutation []uint64) bool{
+		// A_k(i,j) = 1
+		func(permutation []uint64) bool {
+			lesson, subjectProfessor, group := permutation[2], permutation[3], permutation[4]
+
+			return lesson == math.MaxUint64 ||
+				subjectProfessor == math.MaxUint64 ||
+				group == math.MaxUint64 ||
+
+				// Actual predicate
+				state.evaluator.Teaches(group, subjectProfessor, lesson)
+		},
+		// Assigned(r, i) = 1
+		func(permutation []uint64) bool {
+			subjectProfessor, group, room := permutation[3], permutation[4], permutation[5]
+
+			return subjectProfessor == math.MaxUint64 ||
+				group == math.MaxUint64 ||
+				room == math.MaxUint64 ||
+
+				// Actual predicate
+				state.evaluator.Assigned(room, subjectProfessor, group)
+		},
+		// RoomAvailable(r, d, t) = 0
+		func(permutation []uint64) bool {
+			period, day, room := permutation[0], permutation[1], permutation[5]
+
+			return period == math.MaxUint64 ||
+				day == math.MaxUint64 ||
+				room == math.MaxUint64 ||
+
+				// Actual predicate
+				!state.evaluator.RoomAvailable(room, day, period)
+		},
+	})
+
+	clauses := make([][]int64, 0)
+
+	for _, permutation := range permutations {
+		period, day, lesson, subjectProfessor, group, room := permutation[0], permutation[1], permutation[2], permutation[3], permutation[4], permutation[5]
+
+		index := state.indexer.Index(period, day, lesson, subjectProfessor, group, room)
+
+		clauses = append(clauses, []int64{-int64(index)})
+	}
+
+	return clauses
+}
+
 func lessonConstraints(state constraintState) [][]int64 {
 	permutations := state.generator.ConstrainedPermutations([]func(permutation []uint64) bool{
 		// A_k(i,j) = 1
@@ -655,6 +719,10 @@ func completenessConstraints(state constraintState) [][]int64 {
 	for _, triplet := range triplets {
 		lesson, subjectProfessor, group := triplet[0], triplet[1], triplet[2]
 		clause := []int64{}
+		// Candidate entries are only taught if their professor is chosen
+		if choice, ok := state.choices[[2]uint64{subjectProfessor, group}]; ok {
+			clause = append(clause, -choice)
+		}
 		for period := range state.periods {
 			for day := range state.days {
 				for room := range state.rooms {
@@ -827,3 +895,589 @@ func uniquenessConstraints(state constraintState) [][]int64 {
 
 	return clauses
 }
+
+func pinnedConstraints(state constraintState) [][]int64 {
+	clauses := make([][]int64, 0, len(state.modelInput.Pinned))
+
+	for pinned, assignment := range state.modelInput.Pinned {
+		period, day, subjectProfessor, group := assignment.Period, assignment.Day, assignment.SubjectProfessor, assignment.Group
+
+		// At least one of the entry's lessons must be taught at the pinned period and day (and room, if specified)
+		clause := []int64{}
+		for lesson := range state.lessons {
+			for room := range state.rooms {
+				// A_k(i,j) = 1, Assigned(r, i) = 1, Fits(k, r) = 1, Pinned(p, j, r) = 1
+				if state.evaluator.Teaches(group, subjectProfessor, lesson) &&
+					state.evaluator.Assigned(room, subjectProfessor, group) &&
+					state.evaluator.Fits(group, room) &&
+					state.evaluator.Pinned(uint64(pinned), lesson, room) {
+					index := state.indexer.Index(period, day, lesson, subjectProfessor, group, room)
+					clause = append(clause, int64(index))
+				}
+			}
+		}
+		clauses = append(clauses, clause)
+	}
+
+	return clauses
+}
+
+func forbiddenConstraints(state constraintState) [][]int64 {
+	clauses := make([][]int64, 0)
+
+	for forbidden, assignment := range state.modelInput.Forbidden {
+		period, day, subjectProfessor, group := assignment.Period, assignment.Day, assignment.SubjectProfessor, assignment.Group
+
+		for lesson := range state.lessons {
+			for room := range state.rooms {
+				// A_k(i,j) = 1, Assigned(r, i) = 1, Fits(k, r) = 1, Forbidden(f, j, r) = 1
+				if state.evaluator.Teaches(group, subjectProfessor, lesson) &&
+					state.evaluator.Assigned(room, subjectProfessor, group) &&
+					state.evaluator.Fits(group, room) &&
+					state.evaluator.Forbidden(uint64(forbidden), lesson, room) {
+					index := state.indexer.Index(period, day, lesson, subjectProfessor, group, room)
+					clauses = append(clauses, []int64{-int64(index)})
+				}
+			}
+		}
+	}
+
+	return clauses
+}
+
+func simultaneityConstraints(state constraintState) [][]int64 {
+	clauses := make([][]int64, 0)
+
+	// Returns the variables of the given lesson of the entry that may be true at the given period and day
+	candidates := func(period, day, lesson uint64, entryKey [2]uint64) []int64 {
+		subjectProfessor, group := entryKey[0], entryKey[1]
+		variables := []int64{}
+		for room := range state.rooms {
+			// A_k(i,j) = 1, Allowed(i, d, t) = 1, ProfessorAvailable(i, d, t) = 1, Assigned(r, i) = 1, Fits(k, r) = 1
+			if state.evaluator.Teaches(group, subjectProfessor, lesson) &&
+				state.evaluator.Allowed(subjectProfessor, group, day, period) &&
+				state.evaluator.ProfessorAvailable(subjectProfessor, day, period) &&
+				state.evaluator.Assigned(room, subjectProfessor, group) &&
+				state.evaluator.Fits(group, room) {
+				variables = append(variables, int64(state.indexer.Index(period, day, lesson, subjectProfessor, group, room)))
+			}
+		}
+		return variables
+	}
+
+	for _, entryKeys := range state.modelInput.Simultaneous {
+		for _, entryKey1 := range entryKeys {
+			for _, entryKey2 := range entryKeys {
+				if entryKey1 == entryKey2 {
+					continue
+				}
+
+				// Lesson j of the first entry is taught at period t and day d only if lesson j of the second entry is taught at t and d as well (the converse is generated when the entries are swapped)
+				for lesson := range state.lessons {
+					for period := range state.periods {
+						for day := range state.days {
+							variables2 := candidates(period, day, lesson, entryKey2)
+							for _, variable1 := range candidates(period, day, lesson, entryKey1) {
+								clauses = append(clauses, append([]int64{-variable1}, variables2...))
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return clauses
+}
+
+func travelConstraints(state constraintState) [][]int64 {
+	if len(state.modelInput.TravelTimes) == 0 {
+		return [][]int64{}
+	}
+
+	// Representative room of each building, since whether a room can be reached from another only depends on their buildings
+	representatives := make(map[uint64]uint64)
+	for room := range state.rooms {
+		if _, ok := representatives[state.modelInput.Rooms[room].Building]; !ok {
+			representatives[state.modelInput.Rooms[room].Building] = room
+		}
+	}
+	buildings := slices.Sorted(maps.Keys(representatives))
+	reachable := func(building1, building2, period uint64) bool {
+		return state.evaluator.Reachable(representatives[building1], representatives[building2], period)
+	}
+
+	// Whether a lesson at the building, period and day may clash with the building of the lesson right before or after it
+	constrained := func(building, period uint64) bool {
+		return lo.SomeBy(buildings, func(other uint64) bool {
+			return (period+1 < state.periods && !reachable(building, other, period)) || (period > 0 && !reachable(other, building, period-1))
+		})
+	}
+
+	// Auxiliary variables stating whether a professor (kind 0) or a class (kind 1) is at a building at a period and day (indexed by kind,
+	// professor or class, period, day and building), so that the clauses grow with the buildings rather than with every pair of lessons
+	at := make(map[[5]uint64]int64)
+	variable := func(key [5]uint64) int64 {
+		if _, ok := at[key]; !ok {
+			at[key] = state.allocator.Next()
+		}
+		return at[key]
+	}
+
+	clauses := make([][]int64, 0)
+
+	// Link each scheduling variable to the auxiliary variables of its team's professors and its group's classes
+	for _, permutation := range feasiblePermutations(state) {
+		period, day, lesson, subjectProfessor, group, room := permutation[0], permutation[1], permutation[2], permutation[3], permutation[4], permutation[5]
+		building := state.modelInput.Rooms[room].Building
+		if !constrained(building, period) {
+			continue
+		}
+
+		index := int64(state.indexer.Index(period, day, lesson, subjectProfessor, group, room))
+		for _, professor := range state.modelInput.SubjectProfessors[subjectProfessor].Team() {
+			clauses = append(clauses, []int64{-index, variable([5]uint64{0, professor, period, day, building})})
+		}
+		for _, class := range state.modelInput.Groups[group].Classes {
+			clauses = append(clauses, []int64{-index, variable([5]uint64{1, class, period, day, building})})
+		}
+	}
+
+	// t' = t + 1, d = d', Reachable(r, r', t) = 0: nobody is at the buildings of r and r' at consecutive periods
+	keys := slices.SortedFunc(maps.Keys(at), func(a, b [5]uint64) int { return slices.Compare(a[:], b[:]) })
+	for _, key := range keys {
+		kind, whom, period, day, building1 := key[0], key[1], key[2], key[3], key[4]
+		for _, building2 := range buildings {
+			if next, ok := at[[5]uint64{kind, whom, period + 1, day, building2}]; ok && !reachable(building1, building2, period) {
+				clauses = append(clauses, []int64{-at[key], -next})
+			}
+		}
+	}
+
+	return clauses
+}
+
+// Returns the permutations whose variables may be true (i.e. those that are not negated by the permissibility, availability, room-availability, negation and room-negation constraints)
+func feasiblePermutations(state constraintState) [][]uint64 {
+	return state.generator.ConstrainedPermutations([]func(permutation []uint64) bool{
+		// A_k(i,j) = 1
+		func(permutation []uint64) bool {
+			lesson, subjectProfessor, group := permutation[2], permutation[3], permutation[4]
+
+			return lesson == math.MaxUint64 ||
+				subjectProfessor == math.MaxUint64 ||
+				group == math.MaxUint64 ||
+
+				// Actual predicate
+				state.evaluator.Teaches(group, subjectProfessor, lesson)
+		},
+		// Allowed(i, d, t) = 1
+		func(permutation []uint64) bool {
+			period, day, subjectProfessor, group := permutation[0], permutation[1], permutation[3], permutation[4]
+
+			return period == math.MaxUint64 ||
+				day == math.MaxUint64 ||
+				subjectProfessor == math.MaxUint64 ||
+				group == math.MaxUint64 ||
+
+				// Actual predicate
+				state.evaluator.Allowed(subjectProfessor, group, day, period)
+		},
+		// ProfessorAvailable(i, d, t) = 1
+		func(permutation []uint64) bool {
+			period, day, subjectProfessor := permutation[0], permutation[1], permutation[3]
+
+			return period == math.MaxUint64 ||
+				day == math.MaxUint64 ||
+				subjectProfessor == math.MaxUint64 ||
+
+				// Actual predicate
+				state.evaluator.ProfessorAvailable(subjectProfessor, day, period)
+		},
+		// Assigned(r, i) = 1
+		func(permutation []uint64) bool {
+			subjectProfessor, group, room := permutation[3], permutation[4], permutation[5]
+
+			return subjectProfessor == math.MaxUint64 ||
+				group == math.MaxUint64 ||
+				room == math.MaxUint64 ||
+
+				// Actual predicate
+				state.evaluator.Assigned(room, subjectProfessor, group)
+		},
+		// Fits(k, r) = 1
+		func(permutation []uint64) bool {
+			group, room := permutation[4], permutation[5]
+
+			return group == math.MaxUint64 ||
+				room == math.MaxUint64 ||
+
+				// Actual predicate
+				state.evaluator.Fits(group, room)
+		},
+		// RoomAvailable(r, d, t) = 1
+		func(permutation []uint64) bool {
+			period, day, room := permutation[0], permutation[1], permutation[5]
+
+			return period == math.MaxUint64 ||
+				day == math.MaxUint64 ||
+				room == math.MaxUint64 ||
+
+				// Actual predicate
+				state.evaluator.RoomAvailable(room, day, period)
+		},
+	})
+}
+
+func teachingDaysConstraints(state constraintState) [][]int64 {
+	// Auxiliary variables stating whether a professor teaches on a given day (indexed by professor, nil if the professor's days are not bounded)
+	days := state.days / max(state.modelInput.Weeks, 1)
+	teaches := make([][]int64, len(state.modelInput.Professors))
+	for professor, value := range state.modelInput.Professors {
+		if value.MaxTeachingDays > 0 && value.MaxTeachingDays < days {
+			teaches[professor] = make([]int64, state.days)
+			for day := range state.days {
+				teaches[professor][day] = state.allocator.Next()
+			}
+		}
+	}
+
+	clauses := make([][]int64, 0)
+
+	// Link each scheduling variable to the auxiliary variables of its team's professors
+	for _, permutation := range feasiblePermutations(state) {
+		period, day, lesson, subjectProfessor, group, room := permutation[0], permutation[1], permutation[2], permutation[3], permutation[4], permutation[5]
+
+		for _, professor := range state.modelInput.SubjectProfessors[subjectProfessor].Team() {
+			if days := teaches[professor]; days != nil {
+				index := state.indexer.Index(period, day, lesson, subjectProfessor, group, room)
+				clauses = append(clauses, []int64{-int64(index), days[day]})
+			}
+		}
+	}
+
+	// A professor teaches on at most MaxTeachingDays days per week
+	for professor, variables := range teaches {
+		if variables == nil {
+			continue
+		}
+		for week := range max(state.modelInput.Weeks, 1) {
+			clauses = append(clauses, atMost(variables[week*days:(week+1)*days], state.modelInput.Professors[professor].MaxTeachingDays, state.allocator)...)
+		}
+	}
+
+	return clauses
+}
+
+func weeklyLessonsConstraints(state constraintState) [][]int64 {
+	weeks := max(state.modelInput.Weeks, 1)
+	if weeks == 1 {
+		return [][]int64{}
+	}
+	days := state.days / weeks
+
+	// Group the variables of each entry by week
+	taught := make(map[[3]uint64][]int64)
+	for _, permutation := range feasiblePermutations(state) {
+		period, day, lesson, subjectProfessor, group, room := permutation[0], permutation[1], permutation[2], permutation[3], permutation[4], permutation[5]
+		key := [3]uint64{subjectProfessor, group, day / days}
+		taught[key] = append(taught[key], int64(state.indexer.Index(period, day, lesson, subjectProfessor, group, room)))
+	}
+
+	// An entry teaches at most its weekly lessons each week, which amounts to exactly them since every lesson is taught once
+	clauses := make([][]int64, 0)
+	for _, entryKey := range sortedEntryKeys(state.modelInput.Entries) {
+		for week := range weeks {
+			clauses = append(clauses, atMost(taught[[3]uint64{entryKey[0], entryKey[1], week}], lessonsOfWeek(state.modelInput.Entries[entryKey], week), state.allocator)...)
+		}
+	}
+	return clauses
+}
+
+func professorChoiceConstraints(state constraintState) [][]int64 {
+	clauses := make([][]int64, 0)
+
+	// A lesson of a candidate entry is taught only if its professor is chosen
+	for _, permutation := range feasiblePermutations(state) {
+		period, day, lesson, subjectProfessor, group, room := permutation[0], permutation[1], permutation[2], permutation[3], permutation[4], permutation[5]
+		if choice, ok := state.choices[[2]uint64{subjectProfessor, group}]; ok {
+			index := state.indexer.Index(period, day, lesson, subjectProfessor, group, room)
+			clauses = append(clauses, []int64{-int64(index), choice})
+		}
+	}
+
+	// Exactly one professor of each pool is chosen
+	for _, pool := range state.modelInput.Pools {
+		choices := lo.Map(pool, func(entryKey [2]uint64, _ int) int64 { return state.choices[entryKey] })
+		clauses = append(clauses, choices)
+		clauses = append(clauses, atMost(choices, 1, state.allocator)...)
+	}
+
+	// A professor's weekly load (i.e. fixed lessons plus the lessons of its chosen candidate entries) is within its bounds
+	for professor, value := range state.modelInput.Professors {
+		if value.MinLoad == 0 && value.MaxLoad == 0 {
+			continue
+		}
+
+		for week := range max(state.modelInput.Weeks, 1) {
+			// Each choice counts as many times as its entry has lessons in the week
+			literals := make([]int64, 0)
+			for _, entryKey := range sortedEntryKeys(state.choices) {
+				if slices.Contains(state.modelInput.SubjectProfessors[entryKey[0]].Team(), uint64(professor)) {
+					for range lessonsOfWeek(state.modelInput.Entries[entryKey], week) {
+						literals = append(literals, state.choices[entryKey])
+					}
+				}
+			}
+
+			fixed := fixedLoad(state.modelInput, uint64(professor), week)
+			if value.MaxLoad > 0 {
+				clauses = append(clauses, atMost(literals, value.MaxLoad-fixed, state.allocator)...)
+			}
+			if value.MinLoad > fixed {
+				clauses = append(clauses, atLeast(literals, value.MinLoad-fixed, state.allocator)...)
+			}
+		}
+	}
+
+	return clauses
+}
+
+// Returns a professor-choice variable for each candidate entry of the input's pools
+func newChoiceVariables(modelInput ModelInput, allocator *variableAllocator) map[[2]uint64]int64 {
+	choices := make(map[[2]uint64]int64)
+	for _, pool := range modelInput.Pools {
+		for _, entryKey := range pool {
+			choices[entryKey] = allocator.Next()
+		}
+	}
+	return choices
+}
+
+func roomStabilityConstraints(state constraintState) [][]int64 {
+	_, clauses := roomStabilityVariables(state, false)
+	return clauses
+}
+
+// Returns the auxiliary variables stable[scope][room], stating that a lesson within the scope (see roomStabilityScopes) is taught in the room, along
+// with the clauses implying them from the scheduling variables of the entries whose room stability is soft (or hard). If the stability is hard, at
+// most one stable variable per scope is true. In the isolated-room strategy (i.e. the state holds fewer rooms than the input) rooms are assigned
+// after solving, thus each scheduling variable implies instead a stable variable of some fitting room of its entry, shared by all its scopes
+func roomStabilityVariables(state constraintState, soft bool) (map[[3]uint64]map[uint64]int64, [][]int64) {
+	isolated := state.rooms < uint64(len(state.modelInput.Rooms))
+
+	stable := make(map[[3]uint64]map[uint64]int64)
+	variable := func(scope [3]uint64, room uint64) int64 {
+		if _, ok := stable[scope]; !ok {
+			stable[scope] = make(map[uint64]int64)
+		}
+		if _, ok := stable[scope][room]; !ok {
+			stable[scope][room] = state.allocator.Next()
+		}
+		return stable[scope][room]
+	}
+
+	clauses := make([][]int64, 0)
+	for _, permutation := range feasiblePermutations(state) {
+		period, day, lesson, subjectProfessor, group, room := permutation[0], permutation[1], permutation[2], permutation[3], permutation[4], permutation[5]
+		entry := state.modelInput.Entries[[2]uint64{subjectProfessor, group}]
+		if entry.RoomStability == RoomStabilityNone || entry.SoftRoomStability != soft {
+			continue
+		}
+		index := int64(state.indexer.Index(period, day, lesson, subjectProfessor, group, room))
+		scopes := roomStabilityScopes(state.modelInput, subjectProfessor, group, day)
+
+		if !isolated {
+			for _, scope := range scopes {
+				clauses = append(clauses, []int64{-index, variable(scope, room)})
+			}
+			continue
+		}
+
+		rooms := lo.Filter(entry.Rooms, func(room uint64, _ int) bool {
+			return state.modelInput.Rooms[room].Capacity >= groupSize(state.modelInput, group)
+		})
+		for i, scope := range scopes {
+			clause := []int64{-index}
+			for _, room := range rooms {
+				clause = append(clause, variable(scope, room))
+			}
+			clauses = append(clauses, clause)
+
+			// The scopes of the variable are bound to the same room
+			if next := scopes[(i+1)%len(scopes)]; next != scope {
+				for _, room := range rooms {
+					clauses = append(clauses, []int64{-index, -variable(scope, room), variable(next, room)})
+				}
+			}
+		}
+	}
+
+	if !soft {
+		for _, rooms := range stable {
+			clauses = append(clauses, atMost(lo.Values(rooms), 1, state.allocator)...)
+		}
+	}
+
+	return stable, clauses
+}
+
+// Breaks the symmetry among the lessons of each entry, which are interchangeable, by teaching lesson j+1 only after lesson j (by day, then period).
+// Entries with assignments of a specific lesson are left as they are, along with their simultaneous entries (whose lessons are paired)
+func lessonSymmetryConstraints(state constraintState) [][]int64 {
+	fixed := make(map[[2]uint64]bool)
+	for _, assignment := range append(slices.Clone(state.modelInput.Pinned), state.modelInput.Forbidden...) {
+		if assignment.Lesson != Any {
+			fixed[[2]uint64{assignment.SubjectProfessor, assignment.Group}] = true
+		}
+	}
+	for _, entryKeys := range state.modelInput.Simultaneous {
+		if lo.SomeBy(entryKeys, func(entryKey [2]uint64) bool { return fixed[entryKey] }) {
+			for _, entryKey := range entryKeys {
+				fixed[entryKey] = true
+			}
+		}
+	}
+
+	// Group the variables of each lesson by slot, where slots are ordered by day and then period
+	taught := make(map[[3]uint64]map[uint64][]int64)
+	for _, permutation := range feasiblePermutations(state) {
+		period, day, lesson, subjectProfessor, group, room := permutation[0], permutation[1], permutation[2], permutation[3], permutation[4], permutation[5]
+		key := [3]uint64{subjectProfessor, group, lesson}
+		if _, ok := taught[key]; !ok {
+			taught[key] = make(map[uint64][]int64)
+		}
+		slot := day*state.periods + period
+		taught[key][slot] = append(taught[key][slot], int64(state.indexer.Index(period, day, lesson, subjectProfessor, group, room)))
+	}
+
+	clauses := make([][]int64, 0)
+	for _, entryKey := range sortedEntryKeys(state.modelInput.Entries) {
+		if fixed[entryKey] {
+			continue
+		}
+		for lesson := uint64(0); lesson+1 < state.modelInput.Entries[entryKey].Lessons; lesson++ {
+			current, next := taught[[3]uint64{entryKey[0], entryKey[1], lesson}], taught[[3]uint64{entryKey[0], entryKey[1], lesson + 1}]
+
+			// taughtBy is true if and only if lesson j is taught at the slot or an earlier one (0 means lesson j cannot be taught that early)
+			var taughtBy int64
+			for slot := range state.days * state.periods {
+				// Lesson j+1 is taught at the slot only if lesson j is taught at an earlier one
+				for _, variable := range next[slot] {
+					if taughtBy == 0 {
+						clauses = append(clauses, []int64{-variable})
+					} else {
+						clauses = append(clauses, []int64{-variable, taughtBy})
+					}
+				}
+				if len(current[slot]) == 0 {
+					continue
+				}
+
+				variable := state.allocator.Next()
+				clause := []int64{-variable}
+				if taughtBy != 0 {
+					clauses = append(clauses, []int64{-taughtBy, variable})
+					clause = append(clause, taughtBy)
+				}
+				for _, index := range current[slot] {
+					clauses = append(clauses, []int64{-index, variable})
+				}
+				clauses = append(clauses, append(clause, current[slot]...))
+				taughtBy = variable
+			}
+		}
+	}
+
+	return clauses
+}
+
+// Breaks the symmetry among interchangeable rooms (see interchangeableRooms), since swapping two of them throughout a timetable yields another one
+// of the same cost: going through the lessons that may be taught in them in a fixed order, each room of a set is used only after the preceding one
+// (i.e. value precedence)
+func roomSymmetryConstraints(state constraintState) [][]int64 {
+	// Lessons (i.e. <period, day, lesson, subjectProfessor, group> tuples) that may be taught in each room
+	positions := make(map[uint64][][5]uint64)
+	for _, permutation := range feasiblePermutations(state) {
+		room := permutation[5]
+		positions[room] = append(positions[room], [5]uint64{permutation[0], permutation[1], permutation[2], permutation[3], permutation[4]})
+	}
+
+	clauses := make([][]int64, 0)
+	for _, rooms := range interchangeableRooms(state.modelInput) {
+		// Interchangeable rooms may hold the same lessons, thus the positions of the first one serve them all
+		ordered := slices.Clone(positions[rooms[0]])
+		slices.SortFunc(ordered, func(position1, position2 [5]uint64) int { return slices.Compare(position1[:], position2[:]) })
+		index := func(position [5]uint64, room uint64) int64 {
+			return int64(state.indexer.Index(position[0], position[1], position[2], position[3], position[4], room))
+		}
+
+		for i := range len(rooms) - 1 {
+			room1, room2 := rooms[i], rooms[i+1]
+
+			// usedBy is true if and only if the first room is used at the position or an earlier one (0 means before the first position)
+			var usedBy int64
+			for _, position := range ordered {
+				// The second room is used at the position only if the first one is used at an earlier one
+				if usedBy == 0 {
+					clauses = append(clauses, []int64{-index(position, room2)})
+				} else {
+					clauses = append(clauses, []int64{-index(position, room2), usedBy})
+				}
+
+				variable := state.allocator.Next()
+				clause := []int64{-variable, index(position, room1)}
+				if usedBy != 0 {
+					clauses = append(clauses, []int64{-usedBy, variable})
+					clause = append(clause, usedBy)
+				}
+				clauses = append(clauses, []int64{-index(position, room1), variable}, clause)
+				usedBy = variable
+			}
+		}
+	}
+
+	return clauses
+}
+
+// Returns the sets (of two or more) of interchangeable rooms, i.e. rooms with the same capacity, features, building and availability, allowed for
+// the same entries and not referenced by any pinned or forbidden assignment
+func interchangeableRooms(modelInput ModelInput) [][]uint64 {
+	referenced := make(map[uint64]bool)
+	for _, assignment := range append(slices.Clone(modelInput.Pinned), modelInput.Forbidden...) {
+		referenced[assignment.Room] = true
+	}
+	entryKeys := sortedEntryKeys(modelInput.Entries)
+	memberships := lo.Map(modelInput.Rooms, func(_ Room, room int) [][2]uint64 {
+		return lo.Filter(entryKeys, func(entryKey [2]uint64, _ int) bool {
+			return slices.Contains(modelInput.Entries[entryKey].Rooms, uint64(room))
+		})
+	})
+	features := lo.Map(modelInput.Rooms, func(room Room, _ int) []string {
+		features := slices.Clone(room.Features)
+		slices.Sort(features)
+		return features
+	})
+
+	interchangeable := func(room1, room2 uint64) bool {
+		value1, value2 := modelInput.Rooms[room1], modelInput.Rooms[room2]
+		return value1.Capacity == value2.Capacity &&
+			value1.Building == value2.Building &&
+			slices.Equal(features[room1], features[room2]) &&
+			slices.EqualFunc(value1.Availability, value2.Availability, slices.Equal[[]bool]) &&
+			slices.Equal(memberships[room1], memberships[room2])
+	}
+
+	sets := make([][]uint64, 0)
+	for room := range uint64(len(modelInput.Rooms)) {
+		if referenced[room] || len(memberships[room]) == 0 {
+			continue
+		}
+		if i := slices.IndexFunc(sets, func(set []uint64) bool { return interchangeable(set[0], room) }); i >= 0 {
+			sets[i] = append(sets[i], room)
+		} else {
+			sets = append(sets, []uint64{room})
+		}
+	}
+	return lo.Filter(sets, func(set []uint64, _ int) bool { return len(set) > 1 })
+}
@@ -0,0 +1,292 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSymmetryBreaking(t *testing.T) {
+	// Arrange
+	rawInput := rawModelInput{ // Luciano teaches Logica to CC-111 twice in either classroom, on two days with two periods each
+		Subjects:   []Subject{{Id: 0, Name: "Logica"}},
+		Professors: []Professor{{Id: 0, Name: "Luciano", Availability: [][]bool{{true, true}, {true, true}}}},
+		Classes:    []rawClass{{Id: 0, Name: "CC-111", Size: 30}},
+		Rooms:      []Room{{Id: 0, Name: "Aula 1", Capacity: 50}, {Id: 1, Name: "Aula 2", Capacity: 50}},
+		Entries: []rawEntry{
+			{Subject: 0, Professor: 0, Classes: []uint64{0}, Lessons: 2, Permissibility: [][]bool{{true, true}, {true, true}}, Rooms: []uint64{0, 1}},
+		},
+	}
+	input, err := processRawInput(rawInput)
+	assert.Nil(t, err)
+	state := newEmbeddedRoomState(input)
+	rawInput.Pinned = []rawAssignment{{Subject: 0, Professor: 0, Classes: []uint64{0}, Day: 0, Period: 0, Room: lo.ToPtr(uint64(0))}}
+	pinnedInput, err := processRawInput(rawInput)
+	assert.Nil(t, err)
+	scenarios := []struct {
+		constraint  func(state constraintState) [][]int64
+		positives   [][6]uint64 // Positives of a timetable, where every other scheduling variable is false
+		satisfiable bool
+	}{
+		{lessonSymmetryConstraints, [][6]uint64{{0, 0, 0, 0, 0, 0}, {0, 1, 1, 0, 0, 0}}, true},
+		{lessonSymmetryConstraints, [][6]uint64{{0, 1, 0, 0, 0, 0}, {0, 0, 1, 0, 0, 0}}, false}, // Lesson 1 is taught before lesson 0
+		{roomSymmetryConstraints, [][6]uint64{{0, 0, 0, 0, 0, 0}, {0, 1, 1, 0, 0, 1}}, true},
+		{roomSymmetryConstraints, [][6]uint64{{0, 0, 0, 0, 0, 1}, {0, 1, 1, 0, 0, 0}}, false}, // Aula 2 is used before Aula 1
+	}
+
+	// Act
+	rooms, pinnedRooms := interchangeableRooms(input), interchangeableRooms(pinnedInput)
+
+	// Assert
+	assert.Equal(t, [][]uint64{{0, 1}}, rooms)
+	assert.Empty(t, pinnedRooms) // The pinned room cannot be swapped
+	for _, scenario := range scenarios {
+		fixed := make(map[int64]bool)
+		for variable := range state.schedulingVariables() {
+			fixed[int64(variable+1)] = false
+		}
+		for _, positive := range scenario.positives {
+			fixed[int64(state.indexer.Index(positive[0], positive[1], positive[2], positive[3], positive[4], positive[5]))] = true
+		}
+		assert.Equal(t, scenario.satisfiable, satisfiable(scenario.constraint(state), fixed), "positives = %v", scenario.positives)
+	}
+}
+
+func TestSimultaneityConstraints(t *testing.T) {
+	// Arrange
+	input, err := processRawInput(simultaneousRawInput(1))
+	assert.Nil(t, err)
+	state := newEmbeddedRoomState(input)
+	scenarios := []struct {
+		positives   [][6]uint64
+		satisfiable bool
+	}{
+		{[][6]uint64{{0, 0, 0, 0, 0, 0}, {0, 0, 0, 1, 1, 1}}, true},
+		{[][6]uint64{{1, 1, 0, 0, 0, 0}, {1, 1, 0, 1, 1, 1}}, true},
+		{[][6]uint64{{0, 0, 0, 0, 0, 0}, {0, 1, 0, 1, 1, 1}}, false}, // The entries are taught on different days
+		{[][6]uint64{{0, 0, 0, 0, 0, 0}, {1, 0, 0, 1, 1, 1}}, false}, // The entries are taught at different periods
+		{[][6]uint64{{0, 0, 0, 0, 0, 0}}, false},                     // Only the first entry is taught
+	}
+
+	// Act
+	clauses := simultaneityConstraints(state)
+
+	// Assert
+	for _, scenario := range scenarios {
+		fixed := make(map[int64]bool)
+		for variable := range state.schedulingVariables() {
+			fixed[int64(variable+1)] = false
+		}
+		for _, positive := range scenario.positives {
+			fixed[int64(state.indexer.Index(positive[0], positive[1], positive[2], positive[3], positive[4], positive[5]))] = true
+		}
+		assert.Equal(t, scenario.satisfiable, satisfiable(clauses, fixed), "positives = %v", scenario.positives)
+	}
+}
+
+func TestTravelConstraints(t *testing.T) {
+	scenarios := []struct {
+		travelTimes bool
+		breaks      []uint64
+		satisfiable bool
+	}{
+		{false, nil, true},
+		{true, []uint64{15}, true},
+		{true, []uint64{5}, false},
+		{true, nil, false}, // No break at all
+	}
+
+	for _, scenario := range scenarios {
+		// Arrange
+		state := newEmbeddedRoomState(travelInput(t, scenario.travelTimes, scenario.breaks))
+		fixed := make(map[int64]bool)
+		for variable := range state.schedulingVariables() {
+			fixed[int64(variable+1)] = false
+		}
+		fixed[int64(state.indexer.Index(0, 0, 0, 0, 0, 0))] = true
+		fixed[int64(state.indexer.Index(1, 0, 0, 1, 1, 1))] = true
+
+		// Act
+		clauses := travelConstraints(state)
+
+		// Assert
+		assert.Equal(t, scenario.satisfiable, satisfiable(clauses, fixed), "travelTimes = %v, breaks = %v", scenario.travelTimes, scenario.breaks)
+	}
+}
+
+func TestTravelConstraintsSharedClass(t *testing.T) {
+	scenarios := []struct {
+		breaks      []uint64
+		satisfiable bool
+	}{
+		{[]uint64{15}, true},
+		{[]uint64{5}, false},
+	}
+
+	for _, scenario := range scenarios {
+		// Arrange
+		input, err := processRawInput(rawModelInput{ // Luciano teaches Logica to CC-111 in Aula 1 and Dalianys teaches it Algebra in Lab 1
+			Subjects: []Subject{{Id: 0, Name: "Logica"}, {Id: 1, Name: "Algebra"}},
+			Professors: []Professor{
+				{Id: 0, Name: "Luciano", Availability: [][]bool{{true}, {true}}},
+				{Id: 1, Name: "Dalianys", Availability: [][]bool{{true}, {true}}},
+			},
+			Classes:     []rawClass{{Id: 0, Name: "CC-111", Size: 30}},
+			Buildings:   []Building{{Id: 0, Name: "Edificio Central"}, {Id: 1, Name: "Edificio de Laboratorios"}},
+			Rooms:       []Room{{Id: 0, Name: "Aula 1", Capacity: 50, Building: 0}, {Id: 1, Name: "Lab 1", Capacity: 50, Building: 1}},
+			TravelTimes: [][]uint64{{0, 10}, {10, 0}},
+			Breaks:      scenario.breaks,
+			Entries: []rawEntry{
+				{Subject: 0, Professor: 0, Classes: []uint64{0}, Lessons: 1, Permissibility: [][]bool{{true}, {true}}, Rooms: []uint64{0}},
+				{Subject: 1, Professor: 1, Classes: []uint64{0}, Lessons: 1, Permissibility: [][]bool{{true}, {true}}, Rooms: []uint64{1}},
+			},
+		})
+		assert.Nil(t, err)
+		state := newEmbeddedRoomState(input)
+		fixed := make(map[int64]bool)
+		for variable := range state.schedulingVariables() {
+			fixed[int64(variable+1)] = false
+		}
+		fixed[int64(state.indexer.Index(0, 0, 0, 0, 0, 0))] = true
+		fixed[int64(state.indexer.Index(1, 0, 0, 1, 0, 1))] = true
+
+		// Act
+		clauses := travelConstraints(state)
+
+		// Assert
+		assert.Equal(t, scenario.satisfiable, satisfiable(clauses, fixed), "breaks = %v", scenario.breaks)
+	}
+}
+
+func TestRoomStabilityVariables(t *testing.T) {
+	scenarios := []struct {
+		stability   string
+		soft        bool
+		positives   [][6]uint64
+		satisfiable bool
+	}{
+		{"entry", false, [][6]uint64{{0, 0, 0, 0, 0, 0}, {0, 1, 1, 0, 0, 0}, {1, 0, 0, 1, 0, 1}}, true},
+		{"entry", false, [][6]uint64{{0, 0, 0, 0, 0, 0}, {0, 1, 1, 0, 0, 1}, {1, 0, 0, 1, 0, 1}}, false}, // Logica is split across rooms
+		{"entry", true, [][6]uint64{{0, 0, 0, 0, 0, 0}, {0, 1, 1, 0, 0, 1}, {1, 0, 0, 1, 0, 1}}, true},
+		{"day", false, [][6]uint64{{0, 0, 0, 0, 0, 0}, {0, 1, 1, 0, 0, 1}, {1, 0, 0, 1, 0, 0}}, true},
+		{"day", false, [][6]uint64{{0, 0, 0, 0, 0, 0}, {0, 1, 1, 0, 0, 0}, {1, 0, 0, 1, 0, 1}}, false}, // CC-111 changes rooms on Monday
+	}
+
+	for _, scenario := range scenarios {
+		// Arrange
+		state := newEmbeddedRoomState(roomStabilityInput(t, scenario.stability, scenario.soft))
+		fixed := make(map[int64]bool)
+		for variable := range state.schedulingVariables() {
+			fixed[int64(variable+1)] = false
+		}
+		for _, positive := range scenario.positives {
+			fixed[int64(state.indexer.Index(positive[0], positive[1], positive[2], positive[3], positive[4], positive[5]))] = true
+		}
+
+		// Act
+		_, clauses := roomStabilityVariables(state, scenario.soft)
+
+		// Assert
+		assert.Equal(t, scenario.satisfiable, satisfiable(clauses, fixed), "stability = %v, soft = %v, positives = %v", scenario.stability, scenario.soft, scenario.positives)
+	}
+}
+
+// Returns an input where Luciano teaches Logica to CC-111 twice and Dalianys teaches it Algebra once, with the given room stability, in either Aula 1
+// or Aula 2 on two days with two periods each
+func roomStabilityInput(t *testing.T, stability string, soft bool) ModelInput {
+	availability := func() [][]bool { return [][]bool{{true, true}, {true, true}} }
+	input, err := processRawInput(rawModelInput{
+		Subjects: []Subject{{Id: 0, Name: "Logica"}, {Id: 1, Name: "Algebra"}},
+		Professors: []Professor{
+			{Id: 0, Name: "Luciano", Availability: availability()},
+			{Id: 1, Name: "Dalianys", Availability: availability()},
+		},
+		Classes: []rawClass{{Id: 0, Name: "CC-111", Size: 30}},
+		Rooms:   []Room{{Id: 0, Name: "Aula 1", Capacity: 50}, {Id: 1, Name: "Aula 2", Capacity: 50}},
+		Entries: []rawEntry{
+			{
+				Subject: 0, Professor: 0, Classes: []uint64{0}, Lessons: 2, Permissibility: availability(), Rooms: []uint64{0, 1},
+				RoomStability: stability, SoftRoomStability: soft,
+			},
+			{
+				Subject: 1, Professor: 1, Classes: []uint64{0}, Lessons: 1, Permissibility: availability(), Rooms: []uint64{0, 1},
+				RoomStability: stability, SoftRoomStability: soft,
+			},
+		},
+	})
+	assert.Nil(t, err)
+	return input
+}
+
+// Returns an input where Luciano teaches Logica to CC-111 in Aula 1 and Algebra to CC-112 in Lab 1, once each on a single day with two periods.
+// Aula 1 and Lab 1 stand in different buildings, 10 minutes apart when travel times are given
+func travelInput(t *testing.T, travelTimes bool, breaks []uint64) ModelInput {
+	rawInput := rawModelInput{
+		Subjects:   []Subject{{Id: 0, Name: "Logica"}, {Id: 1, Name: "Algebra"}},
+		Professors: []Professor{{Id: 0, Name: "Luciano", Availability: [][]bool{{true}, {true}}}},
+		Classes:    []rawClass{{Id: 0, Name: "CC-111", Size: 30}, {Id: 1, Name: "CC-112", Size: 30}},
+		Buildings:  []Building{{Id: 0, Name: "Edificio Central"}, {Id: 1, Name: "Edificio de Laboratorios"}},
+		Rooms:      []Room{{Id: 0, Name: "Aula 1", Capacity: 50, Building: 0}, {Id: 1, Name: "Lab 1", Capacity: 50, Building: 1}},
+		Entries: []rawEntry{
+			{Subject: 0, Professor: 0, Classes: []uint64{0}, Lessons: 1, Permissibility: [][]bool{{true}, {true}}, Rooms: []uint64{0}},
+			{Subject: 1, Professor: 0, Classes: []uint64{1}, Lessons: 1, Permissibility: [][]bool{{true}, {true}}, Rooms: []uint64{1}},
+		},
+		Breaks: breaks,
+	}
+	if travelTimes {
+		rawInput.TravelTimes = [][]uint64{{0, 10}, {10, 0}}
+	}
+	input, err := processRawInput(rawInput)
+	assert.Nil(t, err)
+	return input
+}
+
+func TestTeachingDaysConstraints(t *testing.T) {
+	scenarios := []struct {
+		maxTeachingDays uint64
+		positives       [][6]uint64
+		satisfiable     bool
+	}{
+		{1, [][6]uint64{{0, 0, 0, 0, 0, 0}, {1, 0, 0, 1, 1, 0}}, true},
+		{1, [][6]uint64{{0, 0, 0, 0, 0, 0}, {0, 1, 0, 1, 1, 0}}, false},
+		{2, [][6]uint64{{0, 0, 0, 0, 0, 0}, {0, 1, 0, 1, 1, 0}}, true},
+		{0, [][6]uint64{{0, 0, 0, 0, 0, 0}, {0, 1, 0, 1, 1, 0}}, true}, // No limit
+	}
+
+	for _, scenario := range scenarios {
+		// Arrange
+		state := newEmbeddedRoomState(teachingDaysInput(t, scenario.maxTeachingDays))
+		fixed := make(map[int64]bool)
+		for variable := range state.schedulingVariables() {
+			fixed[int64(variable+1)] = false
+		}
+		for _, positive := range scenario.positives {
+			fixed[int64(state.indexer.Index(positive[0], positive[1], positive[2], positive[3], positive[4], positive[5]))] = true
+		}
+
+		// Act
+		clauses := teachingDaysConstraints(state)
+
+		// Assert
+		assert.Equal(t, scenario.satisfiable, satisfiable(clauses, fixed), "maxTeachingDays = %d, positives = %v", scenario.maxTeachingDays, scenario.positives)
+	}
+}
+
+// Returns an input where Luciano, bounded to the given number of teaching days, teaches Logica to CC-111 and Algebra to CC-112 in Aula 1, once
+// each on two days with two periods each
+func teachingDaysInput(t *testing.T, maxTeachingDays uint64) ModelInput {
+	availability := func() [][]bool { return [][]bool{{true, true}, {true, true}} }
+	input, err := processRawInput(rawModelInput{
+		Subjects:   []Subject{{Id: 0, Name: "Logica"}, {Id: 1, Name: "Algebra"}},
+		Professors: []Professor{{Id: 0, Name: "Luciano", Availability: availability(), MaxTeachingDays: maxTeachingDays}},
+		Classes:    []rawClass{{Id: 0, Name: "CC-111", Size: 30}, {Id: 1, Name: "CC-112", Size: 30}},
+		Rooms:      []Room{{Id: 0, Name: "Aula 1", Capacity: 50}},
+		Entries: []rawEntry{
+			{Subject: 0, Professor: 0, Classes: []uint64{0}, Lessons: 1, Permissibility: availability(), Rooms: []uint64{0}},
+			{Subject: 1, Professor: 0, Classes: []uint64{1}, Lessons: 1, Permissibility: availability(), Rooms: []uint64{0}},
+		},
+	})
+	assert.Nil(t, err)
+	return input
+}
@@ -0,0 +1,151 @@
+package model
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+)
+
+// Cost of a timetable broken down by soft constraint, where each value is already multiplied by its weight
+type Cost struct {
+	PreferredSlots uint64
+	LastPeriod     uint64
+	Gaps           uint64
+	CompactDays    uint64
+	Preferences    uint64
+	DislikedSlots  uint64
+	RoomChanges    uint64
+	RoomStability  uint64
+}
+
+func (cost Cost) Total() uint64 {
+	return cost.PreferredSlots + cost.LastPeriod + cost.Gaps + cost.CompactDays + cost.Preferences + cost.DislikedSlots + cost.RoomChanges + cost.RoomStability
+}
+
+func (cost Cost) String() string {
+	return fmt.Sprintf("%d (preferred-slots: %d, last-period: %d, gaps: %d, compact-days: %d, preferences: %d, disliked-slots: %d, room-changes: %d, room-stability: %d)", cost.Total(), cost.PreferredSlots, cost.LastPeriod, cost.Gaps, cost.CompactDays, cost.Preferences, cost.DislikedSlots, cost.RoomChanges, cost.RoomStability)
+}
+
+// Evaluates the soft constraints' cost of the timetable according to the input's weights
+func Evaluate(timetable [][6]uint64, modelInput ModelInput) Cost {
+	totalPeriods, _, _, _, _, _ := getAttributes(modelInput)
+	weights := modelInput.Weights
+
+	cost := Cost{}
+	attendedPeriods := make(map[[2]uint64]map[uint64]bool) // Periods attended by each class on each day
+	attendedRooms := make(map[[3]uint64]uint64)            // Room attended by each class at each period and day
+	teachingDays := make(map[[2]uint64]bool)               // Days each professor teaches on
+	stableRooms := make(map[[3]uint64]map[uint64]bool)     // Rooms within each soft room-stability scope
+	for _, positive := range timetable {
+		period, day, subjectProfessor, group := positive[0], positive[1], positive[3], positive[4]
+
+		if !preferred(modelInput, subjectProfessor, day, period) {
+			cost.PreferredSlots += weights.PreferredSlots
+		}
+		cost.Preferences += preferencePenalty(modelInput, subjectProfessor, day, period) * weights.Preferences
+		if disliked(modelInput, subjectProfessor, day, period) {
+			cost.DislikedSlots += weights.DislikedSlots
+		}
+		if period == totalPeriods-1 {
+			cost.LastPeriod += weights.LastPeriod
+		}
+
+		for _, class := range modelInput.Groups[group].Classes {
+			key := [2]uint64{class, day}
+			if _, ok := attendedPeriods[key]; !ok {
+				attendedPeriods[key] = make(map[uint64]bool)
+			}
+			attendedPeriods[key][period] = true
+			attendedRooms[[3]uint64{class, day, period}] = positive[5]
+		}
+		for _, professor := range modelInput.SubjectProfessors[subjectProfessor].Team() {
+			teachingDays[[2]uint64{professor, day}] = true
+		}
+
+		if modelInput.Entries[[2]uint64{subjectProfessor, group}].SoftRoomStability {
+			for _, scope := range roomStabilityScopes(modelInput, subjectProfessor, group, day) {
+				if _, ok := stableRooms[scope]; !ok {
+					stableRooms[scope] = make(map[uint64]bool)
+				}
+				stableRooms[scope][positive[5]] = true
+			}
+		}
+	}
+
+	// A gap is an idle period between the first and the last lesson of a class on a given day
+	for _, periods := range attendedPeriods {
+		first, last := totalPeriods, uint64(0)
+		for period := range periods {
+			first, last = min(first, period), max(last, period)
+		}
+		cost.Gaps += (last - first + 1 - uint64(len(periods))) * weights.Gaps
+	}
+	cost.CompactDays = uint64(len(teachingDays)) * weights.CompactDays
+
+	// A room change occurs when a class attends consecutive periods in different rooms
+	for key, room := range attendedRooms {
+		if nextRoom, ok := attendedRooms[[3]uint64{key[0], key[1], key[2] + 1}]; ok && nextRoom != room {
+			cost.RoomChanges += weights.RoomChanges
+		}
+	}
+
+	// Every room beyond the first one within a soft room-stability scope is penalized
+	for _, rooms := range stableRooms {
+		cost.RoomStability += uint64(len(rooms)-1) * weights.RoomStability
+	}
+
+	return cost
+}
+
+// Satisfaction of a professor with the timetable, given by the number of lessons taught at slots of each preference grade
+type Satisfaction struct {
+	Professor uint64
+	Preferred uint64
+	Neutral   uint64
+	Disliked  uint64
+}
+
+// Returns the satisfaction score between 0 (every lesson at a disliked slot) and 1 (every lesson at a preferred slot)
+func (satisfaction Satisfaction) Score() float64 {
+	lessons := satisfaction.Preferred + satisfaction.Neutral + satisfaction.Disliked
+	if lessons == 0 {
+		return 1
+	}
+	return (float64(satisfaction.Preferred) + float64(satisfaction.Neutral)/2) / float64(lessons)
+}
+
+func (satisfaction Satisfaction) String() string {
+	return fmt.Sprintf("%.2f (preferred: %d, neutral: %d, disliked: %d)", satisfaction.Score(), satisfaction.Preferred, satisfaction.Neutral, satisfaction.Disliked)
+}
+
+// Returns the satisfaction of each professor with a preference matrix, sorted by professor
+func ProfessorSatisfaction(timetable [][6]uint64, modelInput ModelInput) []Satisfaction {
+	satisfactions := make(map[uint64]*Satisfaction)
+	for _, positive := range timetable {
+		period, day, subjectProfessor := positive[0], positive[1], positive[3]
+		for _, professor := range modelInput.SubjectProfessors[subjectProfessor].Team() {
+			if len(modelInput.Professors[professor].Preferences) == 0 {
+				continue
+			}
+
+			if _, ok := satisfactions[professor]; !ok {
+				satisfactions[professor] = &Satisfaction{Professor: professor}
+			}
+			switch professorPreference(modelInput, professor, day, period) {
+			case PreferencePreferred:
+				satisfactions[professor].Preferred++
+			case PreferenceNeutral:
+				satisfactions[professor].Neutral++
+			default:
+				satisfactions[professor].Disliked++
+			}
+		}
+	}
+
+	result := make([]Satisfaction, 0, len(satisfactions))
+	for _, satisfaction := range satisfactions {
+		result = append(result, *satisfaction)
+	}
+	slices.SortFunc(result, func(a, b Satisfaction) int { return cmp.Compare(a.Professor, b.Professor) })
+	return result
+}
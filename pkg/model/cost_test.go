@@ -0,0 +1,48 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoomStability(t *testing.T) {
+	scenarios := []struct {
+		soft  bool
+		rooms [2]uint64 // Rooms of Logica's two lessons
+		valid bool
+		cost  uint64
+	}{
+		{false, [2]uint64{1, 1}, true, 0},
+		{false, [2]uint64{0, 1}, false, 0},
+		{true, [2]uint64{1, 1}, true, 0},
+		{true, [2]uint64{0, 1}, true, 3}, // The second room is penalized
+	}
+
+	for _, scenario := range scenarios {
+		// Arrange
+		rawInput := rawModelInput{ // Luciano teaches Logica to CC-111 on two days with a single period, in either classroom
+			Subjects:   []Subject{{Id: 0, Name: "Logica"}},
+			Professors: []Professor{{Id: 0, Name: "Luciano", Availability: [][]bool{{true, true}}}},
+			Classes:    []rawClass{{Id: 0, Name: "CC-111", Size: 30}},
+			Rooms:      []Room{{Id: 0, Name: "Aula 1", Capacity: 50}, {Id: 1, Name: "Aula 2", Capacity: 50}},
+			Entries: []rawEntry{{
+				Subject: 0, Professor: 0, Classes: []uint64{0}, Lessons: 2, Permissibility: [][]bool{{true, true}}, Rooms: []uint64{0, 1},
+				RoomStability: "entry", SoftRoomStability: scenario.soft,
+			}},
+			Weights: Weights{RoomStability: 3},
+		}
+		input, err := processRawInput(rawInput)
+		assert.Nil(t, err)
+		timetable := [][6]uint64{{0, 0, 0, 0, 0, scenario.rooms[0]}, {0, 1, 1, 0, 0, scenario.rooms[1]}}
+
+		// Act
+		valid, cost := verify(timetable, input), Evaluate(timetable, input)
+
+		// Assert
+		assert.Equal(t, scenario.valid, valid, "scenario = %v", scenario)
+		if scenario.valid {
+			assert.Equal(t, scenario.cost, cost.RoomStability, "scenario = %v", scenario)
+		}
+	}
+}
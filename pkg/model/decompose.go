@@ -0,0 +1,244 @@
+package model
+
+import (
+	"runtime"
+	"slices"
+	"sync"
+
+	"github.com/samber/lo"
+)
+
+type decomposedTimetabler struct {
+	timetabler Timetabler
+}
+
+// Returns a timetabler that splits the input into independent components (i.e. sets of entries sharing no classes, professors nor rooms), builds
+// each of them with the given timetabler in parallel and merges the resulting timetables
+func NewDecomposedTimetabler(timetabler Timetabler) Timetabler {
+	return &decomposedTimetabler{
+		timetabler: timetabler,
+	}
+}
+
+func (timetabler *decomposedTimetabler) Build(modelInput ModelInput) (timetable [][6]uint64, variables uint64, clauses uint64, err error) {
+	components := decompose(modelInput)
+	if len(components) <= 1 {
+		return timetabler.timetabler.Build(modelInput)
+	}
+
+	type result struct {
+		timetable          [][6]uint64
+		variables, clauses uint64
+		err                error
+	}
+	results := make([]result, len(components))
+
+	// Build the components on different goroutines, running at most as many solvers at once as there are CPUs
+	var wait sync.WaitGroup
+	semaphore := make(chan struct{}, runtime.NumCPU())
+	for i, component := range components {
+		wait.Add(1)
+		go func() {
+			defer wait.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			timetable, variables, clauses, err := timetabler.timetabler.Build(component.modelInput)
+			results[i] = result{timetable: component.merge(timetable), variables: variables, clauses: clauses, err: err}
+		}()
+	}
+	wait.Wait()
+
+	// Merge the timetables, where the input is unsatisfiable if any of its components is
+	timetable = make([][6]uint64, 0)
+	for _, result := range results {
+		variables, clauses = variables+result.variables, clauses+result.clauses
+		if result.err != nil {
+			return nil, 0, 0, result.err
+		} else if result.timetable == nil {
+			timetable = nil
+		} else if timetable != nil {
+			timetable = append(timetable, result.timetable...)
+		}
+	}
+	return timetable, variables, clauses, nil
+}
+
+func (timetabler *decomposedTimetabler) Verify(timetable [][6]uint64, modelInput ModelInput) bool {
+	return timetabler.timetabler.Verify(timetable, modelInput)
+}
+
+// Independent part of an input, whose subject-professors, groups and rooms are renumbered
+type component struct {
+	modelInput        ModelInput
+	subjectProfessors []uint64 // Original subject-professor of each of the component's ones
+	groups            []uint64 // Original group of each of the component's ones
+	rooms             []uint64 // Original room of each of the component's ones
+}
+
+// Translates the component's timetable back into positives of the original input (a nil timetable stays nil)
+func (component component) merge(timetable [][6]uint64) [][6]uint64 {
+	if timetable == nil {
+		return nil
+	}
+	return lo.Map(timetable, func(positive [6]uint64, _ int) [6]uint64 {
+		return [6]uint64{positive[0], positive[1], positive[2], component.subjectProfessors[positive[3]], component.groups[positive[4]], component.rooms[positive[5]]}
+	})
+}
+
+// Returns the key the original entry has within the component
+func (component component) local(entryKey [2]uint64) [2]uint64 {
+	return [2]uint64{uint64(slices.Index(component.subjectProfessors, entryKey[0])), uint64(slices.Index(component.groups, entryKey[1]))}
+}
+
+// Splits the input into the connected components of its interaction graph, where two entries interact if their groups share a class, their teams
+// share a professor, their allowed rooms overlap, or they belong to the same pool or simultaneous group
+func decompose(modelInput ModelInput) []component {
+	entryKeys := sortedEntryKeys(modelInput.Entries)
+	index := make(map[[2]uint64]int)
+	for i, entryKey := range entryKeys {
+		index[entryKey] = i
+	}
+
+	// Union-find over the entries
+	parents := lo.Range(len(entryKeys))
+	var find func(i int) int
+	find = func(i int) int {
+		if parents[i] != i {
+			parents[i] = find(parents[i])
+		}
+		return parents[i]
+	}
+	union := func(entryKey1, entryKey2 [2]uint64) {
+		parents[find(index[entryKey1])] = find(index[entryKey2])
+	}
+
+	for i, entryKey1 := range entryKeys {
+		for _, entryKey2 := range entryKeys[i+1:] {
+			team1, team2 := modelInput.SubjectProfessors[entryKey1[0]].Team(), modelInput.SubjectProfessors[entryKey2[0]].Team()
+			if modelInput.GroupsGraph[entryKey1[1]][entryKey2[1]] ||
+				lo.Some(team1, team2) ||
+				lo.Some(modelInput.Entries[entryKey1].Rooms, modelInput.Entries[entryKey2].Rooms) {
+				union(entryKey1, entryKey2)
+			}
+		}
+	}
+	for _, entryKeys := range append(slices.Clone(modelInput.Pools), modelInput.Simultaneous...) {
+		for _, entryKey := range entryKeys {
+			union(entryKeys[0], entryKey)
+		}
+	}
+
+	// Group the entries by component, in order of their first entry
+	grouped := make(map[int][][2]uint64)
+	roots := make([]int, 0)
+	for i, entryKey := range entryKeys {
+		root := find(i)
+		if _, ok := grouped[root]; !ok {
+			roots = append(roots, root)
+		}
+		grouped[root] = append(grouped[root], entryKey)
+	}
+
+	return lo.Map(roots, func(root int, _ int) component { return newComponent(modelInput, grouped[root]) })
+}
+
+// Returns the component of the input made of the given entries (along with their assignments, pools and simultaneous groups)
+func newComponent(modelInput ModelInput, entryKeys [][2]uint64) component {
+	inComponent := func(entryKey [2]uint64) bool { return slices.Contains(entryKeys, entryKey) }
+	pinned := lo.Filter(modelInput.Pinned, func(assignment Assignment, _ int) bool {
+		return inComponent([2]uint64{assignment.SubjectProfessor, assignment.Group})
+	})
+	forbidden := lo.Filter(modelInput.Forbidden, func(assignment Assignment, _ int) bool {
+		return inComponent([2]uint64{assignment.SubjectProfessor, assignment.Group})
+	})
+
+	// Collect the subject-professors, groups and rooms of the component, sorted to preserve their original order
+	subjectProfessors, groups, rooms := make([]uint64, 0), make([]uint64, 0), make([]uint64, 0)
+	for _, entryKey := range entryKeys {
+		subjectProfessors = append(subjectProfessors, entryKey[0])
+		groups = append(groups, entryKey[1])
+		rooms = append(rooms, modelInput.Entries[entryKey].Rooms...)
+	}
+	for _, assignment := range append(slices.Clone(pinned), forbidden...) {
+		if assignment.Room != Any {
+			rooms = append(rooms, assignment.Room)
+		}
+	}
+	subjectProfessors, groups, rooms = sortedUnique(subjectProfessors), sortedUnique(groups), sortedUnique(rooms)
+	subjectProfessorOf := func(subjectProfessor uint64) uint64 { return uint64(slices.Index(subjectProfessors, subjectProfessor)) }
+	groupOf := func(group uint64) uint64 { return uint64(slices.Index(groups, group)) }
+	roomOf := func(room uint64) uint64 {
+		if room == Any {
+			return Any
+		}
+		return uint64(slices.Index(rooms, room))
+	}
+	entryKeyOf := func(entryKey [2]uint64) [2]uint64 { return [2]uint64{subjectProfessorOf(entryKey[0]), groupOf(entryKey[1])} }
+	assignmentOf := func(assignment Assignment, _ int) Assignment {
+		assignment.SubjectProfessor, assignment.Group, assignment.Room = subjectProfessorOf(assignment.SubjectProfessor), groupOf(assignment.Group), roomOf(assignment.Room)
+		return assignment
+	}
+
+	input := modelInput
+	input.SubjectProfessors = lo.Map(subjectProfessors, func(subjectProfessor uint64, i int) SubjectProfessor {
+		value := modelInput.SubjectProfessors[subjectProfessor]
+		value.Id = uint64(i)
+		return value
+	})
+	input.Groups = lo.Map(groups, func(group uint64, i int) Group {
+		value := modelInput.Groups[group]
+		value.Id = uint64(i)
+		return value
+	})
+	input.Rooms = lo.Map(rooms, func(room uint64, _ int) Room { return modelInput.Rooms[room] })
+	input.Entries = make(map[[2]uint64]Entry)
+	input.Curriculum = make([][]bool, len(groups))
+	for i := range input.Curriculum {
+		input.Curriculum[i] = make([]bool, len(subjectProfessors))
+	}
+	for _, entryKey := range entryKeys {
+		entry := modelInput.Entries[entryKey]
+		entry.SubjectProfessor, entry.Group = subjectProfessorOf(entry.SubjectProfessor), groupOf(entry.Group)
+		entry.Rooms = lo.Map(entry.Rooms, func(room uint64, _ int) uint64 { return roomOf(room) })
+		input.Entries[entryKeyOf(entryKey)] = entry
+		input.Curriculum[entry.Group][entry.SubjectProfessor] = true
+	}
+	input.GroupsGraph = buildGroupsGraph(input.Groups)
+	input.Pinned = lo.Map(pinned, assignmentOf)
+	input.Forbidden = lo.Map(forbidden, assignmentOf)
+	input.Pools, input.Simultaneous = make([][][2]uint64, 0), make([][][2]uint64, 0)
+	for _, pool := range modelInput.Pools {
+		if inComponent(pool[0]) {
+			input.Pools = append(input.Pools, lo.Map(pool, func(entryKey [2]uint64, _ int) [2]uint64 { return entryKeyOf(entryKey) }))
+		}
+	}
+	for _, simultaneous := range modelInput.Simultaneous {
+		if len(simultaneous) > 0 && inComponent(simultaneous[0]) {
+			input.Simultaneous = append(input.Simultaneous, lo.Map(simultaneous, func(entryKey [2]uint64, _ int) [2]uint64 { return entryKeyOf(entryKey) }))
+		}
+	}
+
+	// Professors teaching in other components are not bound by their loads within this one
+	input.Professors = slices.Clone(modelInput.Professors)
+	for professor := range input.Professors {
+		if !lo.SomeBy(input.SubjectProfessors, func(subjectProfessor SubjectProfessor) bool {
+			return slices.Contains(subjectProfessor.Team(), uint64(professor))
+		}) {
+			input.Professors[professor].MinLoad, input.Professors[professor].MaxLoad = 0, 0
+		}
+	}
+
+	return component{
+		modelInput:        input,
+		subjectProfessors: subjectProfessors,
+		groups:            groups,
+		rooms:             rooms,
+	}
+}
+
+func sortedUnique(values []uint64) []uint64 {
+	values = lo.Uniq(values)
+	slices.Sort(values)
+	return values
+}
@@ -0,0 +1,71 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecompose(t *testing.T) {
+	scenarios := []struct {
+		second     rawEntry // Entry taught alongside Logica
+		components int
+	}{
+		{rawEntry{Subject: 1, Professor: 1, Classes: []uint64{1}, Lessons: 1, Rooms: []uint64{0}}, 2},
+		{rawEntry{Subject: 1, Professor: 0, Classes: []uint64{1}, Lessons: 1, Rooms: []uint64{0}}, 1},    // Luciano teaches both entries
+		{rawEntry{Subject: 1, Professor: 1, Classes: []uint64{0}, Lessons: 1, Rooms: []uint64{0}}, 1},    // CC-111 attends both entries
+		{rawEntry{Subject: 1, Professor: 1, Classes: []uint64{1}, Lessons: 1, Rooms: []uint64{0, 1}}, 1}, // Both entries may be taught in Aula 2
+	}
+
+	for _, scenario := range scenarios {
+		// Arrange
+		input, err := processRawInput(decomposableRawInput(scenario.second))
+		assert.Nil(t, err)
+
+		// Act
+		components := decompose(input)
+
+		// Assert
+		assert.Len(t, components, scenario.components, "second entry = %v", scenario.second)
+	}
+}
+
+func TestComponentRenumbering(t *testing.T) {
+	// Arrange
+	input, err := processRawInput(decomposableRawInput(rawEntry{Subject: 1, Professor: 1, Classes: []uint64{1}, Lessons: 1, Rooms: []uint64{0}}))
+	assert.Nil(t, err)
+
+	// Act
+	components := decompose(input)
+
+	// Assert
+	assert.Len(t, components, 2)
+	for _, component := range components {
+		assert.Len(t, component.modelInput.Entries, 1)
+		assert.Len(t, component.modelInput.Rooms, 1)
+		assert.Equal(t, []uint64{0}, component.modelInput.Entries[[2]uint64{0, 0}].Rooms)
+	}
+	assert.Equal(t, [][6]uint64{{1, 0, 0, 0, 0, 1}}, components[0].merge([][6]uint64{{1, 0, 0, 0, 0, 0}}))
+	assert.Equal(t, [][6]uint64{{1, 0, 0, 1, 1, 0}}, components[1].merge([][6]uint64{{1, 0, 0, 0, 0, 0}}))
+	assert.Equal(t, [2]uint64{0, 0}, components[1].local([2]uint64{1, 1}))
+}
+
+// Returns a raw input where Luciano teaches Logica to CC-111 in Aula 2 along with the given entry, once each on one of two days with two periods
+// each. Luciano and Dalianys teach the subjects Logica and Algebra to the classes CC-111 and CC-112 in the rooms Aula 1 and Aula 2
+func decomposableRawInput(second rawEntry) rawModelInput {
+	availability := func() [][]bool { return [][]bool{{true, true}, {true, true}} }
+	second.Permissibility = availability()
+	return rawModelInput{
+		Subjects: []Subject{{Id: 0, Name: "Logica"}, {Id: 1, Name: "Algebra"}},
+		Professors: []Professor{
+			{Id: 0, Name: "Luciano", Availability: availability()},
+			{Id: 1, Name: "Dalianys", Availability: availability()},
+		},
+		Classes: []rawClass{{Id: 0, Name: "CC-111", Size: 30}, {Id: 1, Name: "CC-112", Size: 30}},
+		Rooms:   []Room{{Id: 0, Name: "Aula 1", Capacity: 50}, {Id: 1, Name: "Aula 2", Capacity: 50}},
+		Entries: []rawEntry{
+			{Subject: 0, Professor: 0, Classes: []uint64{0}, Lessons: 1, Permissibility: availability(), Rooms: []uint64{1}},
+			second,
+		},
+	}
+}
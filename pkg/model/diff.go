@@ -0,0 +1,110 @@
+package model
+
+import "slices"
+
+// Move of a lesson from the period, day and room of its positive in the previous timetable to those of its positive in the current one
+type Move struct {
+	From [6]uint64
+	To   [6]uint64
+}
+
+// Differences between two timetables of the same (or an edited) input
+type TimetableDiff struct {
+	Added       [][6]uint64       // Lessons without a counterpart in the previous timetable
+	Removed     [][6]uint64       // Lessons without a counterpart in the current timetable
+	Moved       []Move            // Lessons moved to another period or day (and possibly to another room)
+	RoomChanges []Move            // Lessons kept at the same period and day but moved to another room
+	Classes     map[uint64]uint64 // Number of changes affecting each class
+	Professors  map[uint64]uint64 // Number of changes affecting each professor
+}
+
+// Returns the differences between the timetables, whose positives must refer to the given input. Since the lessons of an entry are interchangeable,
+// positives are matched by entry regardless of their lesson: identical slots first, then identical periods and days (i.e. room changes) and finally
+// the remaining ones in chronological order, where lessons left without a counterpart are either added or removed
+func Diff(previous, current [][6]uint64, modelInput ModelInput) TimetableDiff {
+	diff := TimetableDiff{
+		Added:       make([][6]uint64, 0),
+		Removed:     make([][6]uint64, 0),
+		Moved:       make([]Move, 0),
+		RoomChanges: make([]Move, 0),
+		Classes:     make(map[uint64]uint64),
+		Professors:  make(map[uint64]uint64),
+	}
+	previousByEntry, currentByEntry := positivesByEntry(previous), positivesByEntry(current)
+
+	entryKeys := sortedEntryKeys(previousByEntry)
+	for _, entryKey := range sortedEntryKeys(currentByEntry) {
+		if _, ok := previousByEntry[entryKey]; !ok {
+			entryKeys = append(entryKeys, entryKey)
+		}
+	}
+
+	for _, entryKey := range entryKeys {
+		from, to := previousByEntry[entryKey], currentByEntry[entryKey]
+
+		// Discard unchanged lessons and pair lessons taught at the same period and day in different rooms
+		_, from, to = pair(from, to, func(a, b [6]uint64) bool { return a[0] == b[0] && a[1] == b[1] && a[5] == b[5] })
+		roomChanges, from, to := pair(from, to, func(a, b [6]uint64) bool { return a[0] == b[0] && a[1] == b[1] })
+		diff.RoomChanges = append(diff.RoomChanges, roomChanges...)
+
+		// Pair the remaining lessons in chronological order
+		moved := min(len(from), len(to))
+		for i := range moved {
+			diff.Moved = append(diff.Moved, Move{From: from[i], To: to[i]})
+		}
+		diff.Removed = append(diff.Removed, from[moved:]...)
+		diff.Added = append(diff.Added, to[moved:]...)
+
+		// Account for the changes affecting the entry's classes and professors
+		changes := uint64(len(roomChanges) + len(from) + len(to) - moved)
+		if changes == 0 {
+			continue
+		}
+		for _, class := range modelInput.Groups[entryKey[1]].Classes {
+			diff.Classes[class] += changes
+		}
+		for _, professor := range modelInput.SubjectProfessors[entryKey[0]].Team() {
+			diff.Professors[professor] += changes
+		}
+	}
+
+	return diff
+}
+
+// Groups the positives by entry, sorting each group chronologically (i.e. by day and then by period)
+func positivesByEntry(timetable [][6]uint64) map[[2]uint64][][6]uint64 {
+	byEntry := make(map[[2]uint64][][6]uint64)
+	for _, positive := range timetable {
+		entryKey := [2]uint64{positive[3], positive[4]}
+		byEntry[entryKey] = append(byEntry[entryKey], positive)
+	}
+	for _, positives := range byEntry {
+		slices.SortFunc(positives, func(a, b [6]uint64) int {
+			return slices.Compare([]uint64{a[1], a[0], a[5]}, []uint64{b[1], b[0], b[5]})
+		})
+	}
+	return byEntry
+}
+
+func sortedEntryKeys[V any](byEntry map[[2]uint64]V) [][2]uint64 {
+	keys := make([][2]uint64, 0, len(byEntry))
+	for key := range byEntry {
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, func(a, b [2]uint64) int { return slices.Compare(a[:], b[:]) })
+	return keys
+}
+
+// Pairs the positives matching each other, returning the pairs along with the positives left on each side
+func pair(from, to [][6]uint64, match func(a, b [6]uint64) bool) ([]Move, [][6]uint64, [][6]uint64) {
+	pairs := make([]Move, 0)
+	from, to = slices.Clone(from), slices.Clone(to)
+	for i := 0; i < len(from); i++ {
+		if j := slices.IndexFunc(to, func(positive [6]uint64) bool { return match(from[i], positive) }); j >= 0 {
+			pairs = append(pairs, Move{From: from[i], To: to[j]})
+			from, to = slices.Delete(from, i, i+1), slices.Delete(to, j, j+1)
+			i--
+		}
+	}
+	return pairs, from, to
+}
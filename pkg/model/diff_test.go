@@ -0,0 +1,51 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	// Arrange
+	availability := func() [][]bool { return [][]bool{{true, true}, {true, true}} }
+	input, err := processRawInput(rawModelInput{
+		Subjects: []Subject{{Id: 0, Name: "Logica"}, {Id: 1, Name: "Algebra"}},
+		Professors: []Professor{
+			{Id: 0, Name: "Luciano", Availability: availability()},
+			{Id: 1, Name: "Dalianys", Availability: availability()},
+		},
+		Classes: []rawClass{{Id: 0, Name: "CC-111", Size: 30}, {Id: 1, Name: "CC-112", Size: 30}},
+		Rooms:   []Room{{Id: 0, Name: "Aula 1", Capacity: 50}, {Id: 1, Name: "Aula 2", Capacity: 50}},
+		Entries: []rawEntry{
+			{Subject: 0, Professor: 0, Classes: []uint64{0}, Lessons: 2, Permissibility: availability(), Rooms: []uint64{0, 1}},
+			{Subject: 1, Professor: 1, Classes: []uint64{1}, Lessons: 3, Permissibility: availability(), Rooms: []uint64{0, 1}},
+		},
+	})
+	assert.Nil(t, err)
+	previous := [][6]uint64{
+		{0, 0, 0, 0, 0, 0},
+		{1, 1, 1, 0, 0, 0},
+		{0, 0, 0, 1, 1, 1},
+		{1, 0, 1, 1, 1, 1},
+		{1, 1, 2, 1, 1, 0},
+	}
+	current := [][6]uint64{
+		{1, 1, 0, 0, 0, 0}, // Unchanged, although lessons were swapped
+		{0, 0, 1, 0, 0, 0},
+		{0, 0, 0, 1, 1, 0}, // Room change
+		{0, 1, 1, 1, 1, 1}, // Moved
+		// The last lesson was removed
+	}
+
+	// Act
+	diff := Diff(previous, current, input)
+
+	// Assert
+	assert.Empty(t, diff.Added)
+	assert.Equal(t, [][6]uint64{{1, 1, 2, 1, 1, 0}}, diff.Removed)
+	assert.Equal(t, []Move{{From: [6]uint64{0, 0, 0, 1, 1, 1}, To: [6]uint64{0, 0, 0, 1, 1, 0}}}, diff.RoomChanges)
+	assert.Equal(t, []Move{{From: [6]uint64{1, 0, 1, 1, 1, 1}, To: [6]uint64{0, 1, 1, 1, 1, 1}}}, diff.Moved)
+	assert.Equal(t, map[uint64]uint64{1: 3}, diff.Classes)
+	assert.Equal(t, map[uint64]uint64{1: 3}, diff.Professors)
+}
@@ -0,0 +1,128 @@
+package model
+
+import (
+	"slices"
+
+	"github.com/limaJavier/timetabling/pkg/sat"
+
+	"github.com/samber/lo"
+)
+
+// SAT encoding of a timetabling strategy, which allows to reuse its SAT instance (e.g. to enumerate timetables) before decoding the solutions
+type encoding struct {
+	satInstance sat.SAT
+	state       constraintState
+	decode      func(solution sat.SATSolution) ([][6]uint64, error) // Returns an unassignableError if the solution's lessons cannot be assigned rooms
+	projection  map[[4]uint64]int64                                 // Time-projection variables, added on first use (see timeProjection)
+	rooms       bool                                                // Whether the scheduling variables determine the rooms (otherwise rooms are assigned by decode)
+}
+
+// encoder is implemented by the timetablers whose SAT instance can be extended and solved repeatedly
+type encoder interface {
+	encode(modelInput ModelInput) encoding
+	satSolver() sat.SATSolver
+}
+
+// Builds the timetable by solving the encoder's SAT instance, refined until its solution can be decoded (see solve)
+func buildEncoded(encoder encoder, modelInput ModelInput) (timetable [][6]uint64, variables uint64, clauses uint64, err error) {
+	encoding := encoder.encode(modelInput)
+
+	_, timetable, err = encoding.solve(encoder.satSolver())
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	variables, clauses = encoding.satInstance.Variables, uint64(len(encoding.satInstance.Clauses))
+	return timetable, variables, clauses, nil // The timetable is nil if the SAT instance is not satisfiable
+}
+
+// Solves the SAT instance and decodes its solution. Whenever the lessons taught at some period and day cannot be assigned a room each, the
+// instance is refined with clauses forbidding the sets of lessons that violate Hall's condition (see blockingClause) and solved again (i.e.
+// counterexample-guided abstraction refinement), until the solution can be decoded or the instance is unsatisfiable, in which case a nil solution
+// is returned
+func (encoding *encoding) solve(solver sat.SATSolver) (sat.SATSolution, [][6]uint64, error) {
+	for {
+		solution, err := solver.Solve(encoding.satInstance)
+		if err != nil || solution == nil {
+			return nil, nil, err
+		}
+
+		timetable, err := encoding.decode(solution)
+		if unassignable, ok := err.(unassignableError); ok {
+			for _, violator := range unassignable.violators {
+				encoding.satInstance.Clauses = append(encoding.satInstance.Clauses, encoding.blockingClause(violator))
+			}
+			continue
+		} else if err != nil {
+			return nil, nil, err
+		}
+		return solution, timetable, nil
+	}
+}
+
+// Returns the clause forbidding the violator's variables to be true simultaneously, where each scheduling variable is replaced by the projection
+// variable of its entry, period and day (see timeProjection), since the rooms an entry can take at a period and day do not depend on the lesson
+// taught there. Thus the clause rules out the violation for every permutation of the entries' lessons at once. Scheduling variables whose rooms do
+// depend on the lesson (i.e. restricted by a pinned or forbidden assignment of a specific lesson) and auxiliary ones are kept as they are
+func (encoding *encoding) blockingClause(violator []int64) []int64 {
+	clause := make([]int64, 0, len(violator))
+	for _, variable := range violator {
+		if uint64(variable) > encoding.state.schedulingVariables() {
+			clause = append(clause, -variable)
+			continue
+		}
+
+		period, day, _, subjectProfessor, group, _ := encoding.state.indexer.Attributes(uint64(variable))
+		lessonRooms := func(assignment Assignment) bool {
+			return assignment.Period == period && assignment.Day == day && assignment.SubjectProfessor == subjectProfessor && assignment.Group == group &&
+				assignment.Lesson != Any && assignment.Room != Any
+		}
+		if lo.SomeBy(encoding.state.modelInput.Pinned, lessonRooms) || lo.SomeBy(encoding.state.modelInput.Forbidden, lessonRooms) {
+			clause = append(clause, -variable)
+			continue
+		}
+		clause = append(clause, -encoding.timeProjection()[[4]uint64{subjectProfessor, group, period, day}])
+	}
+	slices.Sort(clause)
+	return slices.Compact(clause)
+}
+
+// Extends the SAT instance with the clauses stating that s(e, t, d) is true if and only if some lesson of entry e is taught at period t and day d
+// (regardless of its lesson and room), returning the s variables by [4]uint64{subjectProfessor, group, period, day}. The clauses are added once,
+// later calls return the same variables
+func (encoding *encoding) timeProjection() map[[4]uint64]int64 {
+	if encoding.projection != nil {
+		return encoding.projection
+	}
+
+	attending := make(map[[4]uint64][]int64)
+	for _, permutation := range feasiblePermutations(encoding.state) {
+		period, day, lesson, subjectProfessor, group, room := permutation[0], permutation[1], permutation[2], permutation[3], permutation[4], permutation[5]
+		key := [4]uint64{subjectProfessor, group, period, day}
+		attending[key] = append(attending[key], int64(encoding.state.indexer.Index(period, day, lesson, subjectProfessor, group, room)))
+	}
+
+	projection := make(map[[4]uint64]int64)
+	for key, variables := range attending {
+		projection[key] = encoding.state.allocator.Next()
+		for _, variable := range variables {
+			encoding.satInstance.Clauses = append(encoding.satInstance.Clauses, []int64{-variable, projection[key]})
+		}
+		encoding.satInstance.Clauses = append(encoding.satInstance.Clauses, append([]int64{-projection[key]}, variables...))
+	}
+	encoding.satInstance.Variables = encoding.state.allocator.Last()
+	encoding.projection = projection
+
+	return projection
+}
+
+// Extends the SAT instance with the clauses stating that the returned variable is true whenever some lesson of the entry is taught at the period,
+// day and room, which is only meaningful if the scheduling variables determine the rooms
+func (encoding *encoding) roomProjection(period, day, subjectProfessor, group, room uint64) int64 {
+	variable := encoding.state.allocator.Next()
+	for lesson := range encoding.state.modelInput.Entries[[2]uint64{subjectProfessor, group}].Lessons {
+		index := encoding.state.indexer.Index(period, day, lesson, subjectProfessor, group, room)
+		encoding.satInstance.Clauses = append(encoding.satInstance.Clauses, []int64{-int64(index), variable})
+	}
+	encoding.satInstance.Variables = encoding.state.allocator.Last()
+	return variable
+}
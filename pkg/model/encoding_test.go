@@ -0,0 +1,98 @@
+package model
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlockingClause(t *testing.T) {
+	lesson, room := uint64(0), uint64(0)
+	scenarios := []struct {
+		pinned    []rawAssignment
+		projected []bool // Whether the variable of each entry is replaced by its projection
+	}{
+		{nil, []bool{true, true}},
+		{[]rawAssignment{{Subject: 0, Professor: 0, Classes: []uint64{0}}}, []bool{true, true}},                                // Any lesson in any room
+		{[]rawAssignment{{Subject: 0, Professor: 0, Classes: []uint64{0}, Room: &room}}, []bool{true, true}},                   // Any lesson in a specific room
+		{[]rawAssignment{{Subject: 0, Professor: 0, Classes: []uint64{0}, Lesson: &lesson, Room: &room}}, []bool{false, true}}, // A specific lesson in a specific room
+	}
+
+	for _, scenario := range scenarios {
+		// Arrange
+		rawInput := rawModelInput{ // Luciano teaches Logica to CC-111 and Dalianys teaches Algebra to CC-112 in Aula 1, in a single slot
+			Subjects: []Subject{{Id: 0, Name: "Logica"}, {Id: 1, Name: "Algebra"}},
+			Professors: []Professor{
+				{Id: 0, Name: "Luciano", Availability: [][]bool{{true}}},
+				{Id: 1, Name: "Dalianys", Availability: [][]bool{{true}}},
+			},
+			Classes: []rawClass{{Id: 0, Name: "CC-111", Size: 30}, {Id: 1, Name: "CC-112", Size: 30}},
+			Rooms:   []Room{{Id: 0, Name: "Aula 1", Capacity: 50}},
+			Entries: []rawEntry{
+				{Subject: 0, Professor: 0, Classes: []uint64{0}, Lessons: 1, Permissibility: [][]bool{{true}}, Rooms: []uint64{0}},
+				{Subject: 1, Professor: 1, Classes: []uint64{1}, Lessons: 1, Permissibility: [][]bool{{true}}, Rooms: []uint64{0}},
+			},
+			Pinned: scenario.pinned,
+		}
+		input, err := processRawInput(rawInput)
+		assert.Nil(t, err)
+		encoding := NewIsolatedRoomTimetabler(dpllSolver{}, false, 0).(encoder).encode(input)
+		variables := []int64{int64(encoding.state.indexer.Index(0, 0, 0, 0, 0, 0)), int64(encoding.state.indexer.Index(0, 0, 0, 1, 1, 0))}
+
+		// Act
+		clause := encoding.blockingClause(variables)
+
+		// Assert
+		expected := make([]int64, 0)
+		for entry, variable := range variables {
+			if scenario.projected[entry] {
+				variable = encoding.timeProjection()[[4]uint64{uint64(entry), uint64(entry), 0, 0}]
+			}
+			expected = append(expected, -variable)
+		}
+		slices.Sort(expected)
+		assert.Equal(t, expected, clause, "pinned = %v", scenario.pinned)
+	}
+}
+
+func TestRoomRefinement(t *testing.T) {
+	scenarios := []struct {
+		periods     int // Periods of each of the two days
+		satisfiable bool
+	}{
+		{2, true},
+		{1, false}, // Both entries need Aula 1 at the only period
+	}
+
+	for _, scenario := range scenarios {
+		// Arrange
+		availability := func() [][]bool { return lo.Times(scenario.periods, func(_ int) []bool { return []bool{true, true} }) }
+		input, err := processRawInput(rawModelInput{ // Luciano teaches Logica to CC-111 and Dalianys teaches Algebra to CC-112 in Aula 1, twice each
+			Subjects: []Subject{{Id: 0, Name: "Logica"}, {Id: 1, Name: "Algebra"}},
+			Professors: []Professor{
+				{Id: 0, Name: "Luciano", Availability: availability()},
+				{Id: 1, Name: "Dalianys", Availability: availability()},
+			},
+			Classes: []rawClass{{Id: 0, Name: "CC-111", Size: 30}, {Id: 1, Name: "CC-112", Size: 30}},
+			Rooms:   []Room{{Id: 0, Name: "Aula 1", Capacity: 50}},
+			Entries: []rawEntry{
+				{Subject: 0, Professor: 0, Classes: []uint64{0}, Lessons: 2, Permissibility: availability(), Rooms: []uint64{0}},
+				{Subject: 1, Professor: 1, Classes: []uint64{1}, Lessons: 2, Permissibility: availability(), Rooms: []uint64{0}},
+			},
+		})
+		assert.Nil(t, err)
+		timetabler := NewIsolatedRoomTimetabler(dpllSolver{}, false, 0)
+
+		// Act
+		timetable, _, _, err := timetabler.Build(input)
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Equal(t, scenario.satisfiable, timetable != nil, "periods = %d", scenario.periods)
+		if scenario.satisfiable {
+			assert.True(t, verify(timetable, input))
+		}
+	}
+}
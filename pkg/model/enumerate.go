@@ -0,0 +1,67 @@
+package model
+
+import (
+	"errors"
+	"slices"
+
+	"github.com/limaJavier/timetabling/pkg/sat"
+)
+
+// Returns up to n distinct timetables, where two timetables are distinct if they differ in their time-slot projection (i.e. the periods and days
+// each entry is taught at, regardless of lessons and rooms). Each timetable differs from the previous ones in at least distance slots (i.e. at least
+// distance lessons are taught at other periods or days), where a distance of 0 is treated as 1. Fewer timetables are returned when the instance has
+// no more of them; only the embedded-room and isolated-room timetablers support enumeration
+func Enumerate(timetabler Timetabler, modelInput ModelInput, n uint64, distance uint64) ([][][6]uint64, error) {
+	encoder, ok := timetabler.(encoder)
+	if !ok {
+		return nil, errors.New("the timetabler does not support enumeration")
+	}
+
+	encoding := encoder.encode(modelInput)
+	projection := sortedVariables(encoding.timeProjection())
+
+	timetables := make([][][6]uint64, 0, n)
+	_, err := encoding.enumerate(encoder.satSolver(), projection, n, distance, func(timetable [][6]uint64) {
+		timetables = append(timetables, timetable)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return timetables, nil
+}
+
+// Solves the SAT instance repeatedly (see solve), blocking the projection (i.e. sorted projection variables) of every solution by requiring at
+// least distance of its true variables to be false, until n solutions are visited or there are no more of them. Returns the number of solutions
+func (encoding *encoding) enumerate(solver sat.SATSolver, projection []int64, n uint64, distance uint64, visit func(timetable [][6]uint64)) (uint64, error) {
+	visited := uint64(0)
+	for ; visited < n; visited++ {
+		solution, timetable, err := encoding.solve(solver)
+		if err != nil {
+			return 0, err
+		} else if solution == nil { // There are no more solutions
+			break
+		}
+		visit(timetable)
+
+		// Block the projection, requiring at least distance of its true variables to be false
+		unused := make([]int64, 0)
+		for _, literal := range solution {
+			if _, ok := slices.BinarySearch(projection, literal); ok {
+				unused = append(unused, -literal)
+			}
+		}
+		encoding.satInstance.Clauses = append(encoding.satInstance.Clauses, atLeast(unused, max(distance, 1), encoding.state.allocator)...)
+		encoding.satInstance.Variables = encoding.state.allocator.Last()
+	}
+	return visited, nil
+}
+
+func sortedVariables(variables map[[4]uint64]int64) []int64 {
+	sorted := make([]int64, 0, len(variables))
+	for _, variable := range variables {
+		sorted = append(sorted, variable)
+	}
+	slices.Sort(sorted)
+	return sorted
+}
@@ -0,0 +1,65 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnumerate(t *testing.T) {
+	scenarios := []struct {
+		n          uint64
+		timetables int
+	}{
+		{1, 1},
+		{2, 2},
+		{10, 2}, // There are no more timetables
+	}
+
+	for _, scenario := range scenarios {
+		// Arrange
+		input := twoTimetablesInput(t)
+		timetabler := NewEmbeddedRoomTimetabler(dpllSolver{})
+
+		// Act
+		timetables, err := Enumerate(timetabler, input, scenario.n, 1)
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Len(t, timetables, scenario.timetables, "n = %d", scenario.n)
+		for _, timetable := range timetables {
+			assert.True(t, verify(timetable, input))
+		}
+		projections := lo.Map(timetables, func(timetable [][6]uint64, _ int) string {
+			projection := lo.Map(timetable, func(positive [6]uint64, _ int) [3]uint64 { return [3]uint64{positive[3], positive[0], positive[1]} })
+			slices.SortFunc(projection, func(a, b [3]uint64) int { return slices.Compare(a[:], b[:]) })
+			return fmt.Sprint(projection)
+		})
+		assert.Len(t, lo.Uniq(projections), len(timetables), "timetables = %v", timetables) // Timetables differ in their time-slot projection
+	}
+}
+
+// Returns an input with exactly two timetables on a single day with two periods: Luciano teaches Logica to CC-111 and Algebra to CC-112 in Aula 1
+// one after another in either order, while Dalianys can only teach Programacion to CC-113 in Aula 2 at the second period
+func twoTimetablesInput(t *testing.T) ModelInput {
+	availability := func() [][]bool { return [][]bool{{true}, {true}} }
+	input, err := processRawInput(rawModelInput{
+		Subjects: []Subject{{Id: 0, Name: "Logica"}, {Id: 1, Name: "Algebra"}, {Id: 2, Name: "Programacion"}},
+		Professors: []Professor{
+			{Id: 0, Name: "Luciano", Availability: availability()},
+			{Id: 1, Name: "Dalianys", Availability: availability()},
+		},
+		Classes: []rawClass{{Id: 0, Name: "CC-111", Size: 30}, {Id: 1, Name: "CC-112", Size: 30}, {Id: 2, Name: "CC-113", Size: 30}},
+		Rooms:   []Room{{Id: 0, Name: "Aula 1", Capacity: 50}, {Id: 1, Name: "Aula 2", Capacity: 50}},
+		Entries: []rawEntry{
+			{Subject: 0, Professor: 0, Classes: []uint64{0}, Lessons: 1, Permissibility: availability(), Rooms: []uint64{0}},
+			{Subject: 1, Professor: 0, Classes: []uint64{1}, Lessons: 1, Permissibility: availability(), Rooms: []uint64{0}},
+			{Subject: 2, Professor: 1, Classes: []uint64{2}, Lessons: 1, Permissibility: [][]bool{{false}, {true}}, Rooms: []uint64{1}},
+		},
+	})
+	assert.Nil(t, err)
+	return input
+}
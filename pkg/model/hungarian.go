@@ -0,0 +1,69 @@
+package model
+
+import "math"
+
+// Solves the rectangular assignment problem by means of the Hungarian algorithm (with potentials), where costs has at most as many rows as
+// columns. Returns the column assigned to each row, such that no column is assigned twice and the sum of the assigned costs is minimum
+func hungarian(costs [][]int64) []int {
+	rows := len(costs)
+	if rows == 0 {
+		return []int{}
+	}
+	columns := len(costs[0])
+
+	// Rows and columns are 1-indexed, where column 0 is a fictitious one holding the row being assigned
+	rowPotentials, columnPotentials := make([]int64, rows+1), make([]int64, columns+1)
+	assignedRow := make([]int, columns+1) // Row assigned to each column (0 means none)
+	previousColumn := make([]int, columns+1)
+
+	for row := 1; row <= rows; row++ {
+		assignedRow[0] = row
+		current := 0
+		minimum := make([]int64, columns+1)
+		for column := range minimum {
+			minimum[column] = math.MaxInt64
+		}
+		used := make([]bool, columns+1)
+
+		// Grow the alternating tree until it reaches a free column
+		for assignedRow[current] != 0 {
+			used[current] = true
+			currentRow, delta, next := assignedRow[current], int64(math.MaxInt64), 0
+			for column := 1; column <= columns; column++ {
+				if used[column] {
+					continue
+				}
+				if reduced := costs[currentRow-1][column-1] - rowPotentials[currentRow] - columnPotentials[column]; reduced < minimum[column] {
+					minimum[column], previousColumn[column] = reduced, current
+				}
+				if minimum[column] < delta {
+					delta, next = minimum[column], column
+				}
+			}
+			for column := 0; column <= columns; column++ {
+				if used[column] {
+					rowPotentials[assignedRow[column]] += delta
+					columnPotentials[column] -= delta
+				} else {
+					minimum[column] -= delta
+				}
+			}
+			current = next
+		}
+
+		// Augment along the alternating path ending at the free column
+		for current != 0 {
+			previous := previousColumn[current]
+			assignedRow[current] = assignedRow[previous]
+			current = previous
+		}
+	}
+
+	assignment := make([]int, rows)
+	for column := 1; column <= columns; column++ {
+		if assignedRow[column] != 0 {
+			assignment[assignedRow[column]-1] = column - 1
+		}
+	}
+	return assignment
+}
@@ -0,0 +1,55 @@
+package model
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHungarian(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for rows := 1; rows <= 4; rows++ {
+		for columns := rows; columns <= 5; columns++ {
+			// Arrange
+			costs := make([][]int64, rows)
+			for row := range costs {
+				costs[row] = make([]int64, columns)
+				for column := range costs[row] {
+					costs[row][column] = random.Int63n(20)
+				}
+			}
+
+			// Act
+			assignment := hungarian(costs)
+
+			// Assert
+			used, total := make(map[int]bool), int64(0)
+			for row, column := range assignment {
+				assert.False(t, used[column], "column %d is assigned twice", column)
+				used[column] = true
+				total += costs[row][column]
+			}
+			assert.Equal(t, minimumAssignmentCost(costs, 0, make(map[int]bool)), total, "costs = %v", costs)
+		}
+	}
+}
+
+// Computes the minimum cost of assigning distinct columns to the rows from row onwards by brute force
+func minimumAssignmentCost(costs [][]int64, row int, used map[int]bool) int64 {
+	if row == len(costs) {
+		return 0
+	}
+	minimum := int64(-1)
+	for column := range costs[row] {
+		if used[column] {
+			continue
+		}
+		used[column] = true
+		if cost := costs[row][column] + minimumAssignmentCost(costs, row+1, used); minimum < 0 || cost < minimum {
+			minimum = cost
+		}
+		used[column] = false
+	}
+	return minimum
+}
@@ -6,37 +6,85 @@ import (
 	"math"
 	"os"
 	"slices"
+	"strings"
 
 	"github.com/mitchellh/mapstructure"
 	"github.com/samber/lo"
 )
 
 type rawEntry struct {
-	Subject        uint64
-	Professor      uint64
-	Classes        []uint64
-	Lessons        uint64
-	Permissibility [][]bool
-	Rooms          []uint64
+	Subject           uint64
+	Professor         uint64
+	Professors        []uint64 // Team teaching the entry, where the first one is the lead professor (overrides Professor when given)
+	Candidates        []uint64 // Professors the solver chooses one from to teach the entry (overrides Professor when given)
+	Classes           []uint64
+	Lessons           uint64   // Lessons per week, unless WeeklyLessons is given
+	WeeklyLessons     []uint64 // Lessons taught each week (e.g. [1, 0] for A weeks only), one per week
+	Permissibility    [][]bool
+	Rooms             []uint64
+	RequiredFeatures  []string
+	ForbiddenFeatures []string
+	RoomStability     string
+	SoftRoomStability bool
+	UseHomeRoom       bool
+	Tag               string // Free label of the entry (e.g. its stage in staged building)
+}
+
+type rawClass struct {
+	Id           uint64
+	Name         string
+	Size         uint64
+	HomeRoom     *uint64
+	Availability [][]bool
+}
+
+type rawAssignment struct {
+	Subject   uint64
+	Professor uint64
+	Classes   []uint64
+	Lesson    *uint64
+	Week      uint64
+	Day       uint64
+	Period    uint64
+	Room      *uint64
+}
+
+type rawEntryReference struct {
+	Subject   uint64
+	Professor uint64
+	Classes   []uint64
 }
 
 type rawModelInput struct {
-	Subjects   []Subject
-	Professors []Professor
-	Classes    []Class
-	Rooms      []Room
-	Entries    []rawEntry
+	Subjects     []Subject
+	Professors   []Professor
+	Classes      []rawClass
+	Rooms        []Room
+	Buildings    []Building
+	TravelTimes  [][]uint64
+	Breaks       []uint64
+	Weeks        uint64
+	Entries      []rawEntry
+	Pinned       []rawAssignment
+	Forbidden    []rawAssignment
+	Simultaneous [][]rawEntryReference
+	Weights      Weights
 }
 
+// Wildcard used by assignments to match any lesson or any room
+const Any uint64 = math.MaxUint64
+
 type Subject struct {
 	Id   uint64
 	Name string
 }
 
 type Class struct {
-	Id   uint64
-	Name string
-	Size uint64
+	Id           uint64
+	Name         string
+	Size         uint64
+	HomeRoom     uint64   // Room dedicated to the class, used by the entries with UseHomeRoom (Any means the class has no home room)
+	Availability [][]bool // Slots the class is available at (e.g. not at internships), shaped like the professors' availability (if empty, the class is always available)
 }
 
 type Group struct {
@@ -45,29 +93,100 @@ type Group struct {
 }
 
 type Room struct {
-	Id       uint64
-	Name     string
-	Capacity uint64
+	Id           uint64
+	Name         string
+	Capacity     uint64
+	Features     []string // Features provided by the room (e.g. "projector", "computers", "accessible")
+	Building     uint64
+	Availability [][]bool // Slots the room is available at, shaped like the professors' availability (if empty, the room is always available)
+}
+
+type Building struct {
+	Id   uint64
+	Name string
 }
 
 type Professor struct {
-	Id           uint64
-	Name         string
-	Availability [][]bool
+	Id              uint64
+	Name            string
+	Availability    [][]bool
+	MaxTeachingDays uint64     // Maximum number of days per week the professor can teach on (0 means no limit)
+	MinLoad         uint64     // Minimum number of lessons per week the professor must teach (0 means no minimum)
+	MaxLoad         uint64     // Maximum number of lessons per week the professor can teach (0 means no limit)
+	Preferred       [][]bool   // Slots the professor prefers to teach on (if empty, every slot is preferred), derived from Preferences when given
+	Preferences     [][]uint64 // Graded preference for each slot, from PreferenceImpossible to PreferencePreferred (if empty, every slot is neutral)
 }
 
+// Grades of a professor's preference matrix, where impossible slots are treated as unavailable
+const (
+	PreferenceImpossible uint64 = iota
+	PreferenceDisliked
+	PreferenceNeutral
+	PreferencePreferred
+)
+
 type SubjectProfessor struct {
-	Id        uint64
-	Subject   uint64
-	Professor uint64
+	Id           uint64
+	Subject      uint64
+	Professor    uint64   // Lead professor, identifying the subject-professor along with the subject
+	CoProfessors []uint64 // Professors teaching along with the lead one (i.e. team teaching)
+}
+
+// Returns every professor teaching the subject-professor's lessons, starting with the lead one
+func (subjectProfessor SubjectProfessor) Team() []uint64 {
+	return append([]uint64{subjectProfessor.Professor}, subjectProfessor.CoProfessors...)
 }
 
 type Entry struct {
+	SubjectProfessor  uint64
+	Group             uint64
+	Lessons           uint64   // Lessons taught over all weeks
+	WeeklyLessons     []uint64 // Lessons taught each week (if empty, the lessons are not distributed among weeks)
+	Permissibility    [][]bool
+	Rooms             []uint64      // Rooms allowed for the entry, from the most to the least preferred; if not explicitly given, they're derived from the required and forbidden features
+	RequiredFeatures  []string      // Features every allowed room must provide
+	ForbiddenFeatures []string      // Features no allowed room may provide
+	RoomStability     RoomStability // Scope within which the entry's lessons must be taught in the same room
+	SoftRoomStability bool          // Whether rooms beyond the first one within the scope are penalized by Weights.RoomStability instead of forbidden
+	UseHomeRoom       bool          // Whether the entry is taught in the home room of its group's classes, falling back to Rooms if the group does not fit in it
+	Tag               string        // Free label of the entry (e.g. its stage in staged building)
+}
+
+// Scope within which the lessons of an entry must be taught in the same room
+type RoomStability uint64
+
+const (
+	RoomStabilityNone  RoomStability = iota
+	RoomStabilityEntry               // Every lesson of the entry is taught in the same room
+	RoomStabilityDay                 // Each lesson of the entry is taught in the same room as the lessons (of entries with this stability) its classes attend on the same day
+)
+
+var roomStabilities = map[string]RoomStability{
+	"":      RoomStabilityNone,
+	"entry": RoomStabilityEntry,
+	"day":   RoomStabilityDay,
+}
+
+// Assignment of a lesson of the entry (SubjectProfessor, Group) to a period, day and room, where Lesson and Room can be set to Any
+type Assignment struct {
 	SubjectProfessor uint64
 	Group            uint64
-	Lessons          uint64
-	Permissibility   [][]bool
-	Rooms            []uint64
+	Lesson           uint64
+	Day              uint64
+	Period           uint64
+	Room             uint64
+}
+
+// Weights of the soft constraints, where a weight of 0 disables the corresponding soft constraint
+type Weights struct {
+	PreferredSlots uint64 // Penalty for each lesson taught outside its professor's preferred slots
+	LastPeriod     uint64 // Penalty for each lesson taught at the last period of the day
+	Gaps           uint64 // Penalty for each idle period between two lessons of a class on the same day
+	CompactDays    uint64 // Penalty for each day a professor teaches on
+	Preferences    uint64 // Penalty for each grade a lesson's slot falls below PreferencePreferred in its professor's preference matrix
+	DislikedSlots  uint64 // Penalty for each lesson taught at a slot its professor dislikes
+	RoomChanges    uint64 // Penalty for each time a class changes rooms between two consecutive periods of the same day
+	RoomStability  uint64 // Penalty for each room beyond the first one within the scope of an entry with soft room stability
 }
 
 type ModelInput struct {
@@ -79,7 +198,16 @@ type ModelInput struct {
 	Classes           []Class
 	Rooms             []Room
 	Curriculum        [][]bool
-	GroupsGraph       [][]bool // Groups matrix' coordinate (i, j) = true if and only if group_i and group_j have at least one class in common (i.e. it represents an undirected graph where an edge indicate that two groups share a common class). For completeness we assume that groups[i][i] = true for all i
+	GroupsGraph       [][]bool      // Groups matrix' coordinate (i, j) = true if and only if group_i and group_j have at least one class in common (i.e. it represents an undirected graph where an edge indicate that two groups share a common class). For completeness we assume that groups[i][i] = true for all i
+	Pinned            []Assignment  // Assignments that must be present in the timetable
+	Forbidden         []Assignment  // Assignments that must not be present in the timetable
+	Simultaneous      [][][2]uint64 // Groups of entries (identified by their keys) whose lessons must be scheduled at identical periods and days
+	Pools             [][][2]uint64 // Candidate entries (one per candidate professor) of each entry whose professor is chosen by the solver, where exactly one of them is taught
+	Buildings         []Building
+	TravelTimes       [][]uint64 // Minutes it takes to go from building_i to building_j (if empty, travel times are not taken into account)
+	Breaks            []uint64   // Minutes of the break following each period
+	Weeks             uint64     // Weeks the timetable spans, where day d of week w is numbered w*days+d (days being the number of days per week)
+	Weights           Weights
 }
 
 func InputFromJson(file string) (ModelInput, error) {
@@ -101,18 +229,64 @@ func InputFromJson(file string) (ModelInput, error) {
 
 func processRawInput(rawInput rawModelInput) (ModelInput, error) {
 	input := ModelInput{
-		Subjects:   rawInput.Subjects,
-		Professors: rawInput.Professors,
-		Classes:    rawInput.Classes,
-		Rooms:      rawInput.Rooms,
+		Subjects:    rawInput.Subjects,
+		Professors:  rawInput.Professors,
+		Classes:     processRawClasses(rawInput.Classes),
+		Rooms:       rawInput.Rooms,
+		Buildings:   rawInput.Buildings,
+		TravelTimes: rawInput.TravelTimes,
+		Breaks:      rawInput.Breaks,
+		Weeks:       max(rawInput.Weeks, 1),
+		Weights:     rawInput.Weights,
+	}
+
+	//** Validate buildings and travel times
+	if err := validateTravelTimes(input); err != nil {
+		return ModelInput{}, err
+	}
+
+	//** Validate rooms' and classes' availability
+	if err := validateAvailability(input); err != nil {
+		return ModelInput{}, err
+	}
+
+	//** Manage professors' preferences
+	professors, err := processPreferences(input.Professors)
+	if err != nil {
+		return ModelInput{}, err
+	}
+	input.Professors = professors
+
+	//** Repeat the weekly availability and preference matrices over every week
+	expandWeeks(&input)
+
+	//** Expand entries with candidate professors into one candidate entry per professor
+	rawEntries, rawPools, err := expandCandidates(rawInput.Entries, input)
+	if err != nil {
+		return ModelInput{}, err
 	}
 
 	subjectProfessors := make([]SubjectProfessor, 0)
 	associatedClasses := make(map[[2]uint64]map[uint64]bool)
 	groups := make([]Group, 0)
 	entries := make(map[[2]uint64]Entry)
-	for _, rawEntry := range rawInput.Entries {
+	entryKeys := make([][2]uint64, len(rawEntries))
+	for i, rawEntry := range rawEntries {
 		//** Manage subject-professor
+		// Split the team into the lead professor and the co-professors
+		if len(rawEntry.Professors) > 0 {
+			rawEntry.Professor = rawEntry.Professors[0]
+		}
+		coProfessors := make([]uint64, 0)
+		for _, professor := range lo.Drop(rawEntry.Professors, 1) {
+			if professor >= uint64(len(input.Professors)) {
+				return ModelInput{}, fmt.Errorf("entry refers to a non-existing professor %d", professor)
+			} else if professor == rawEntry.Professor || slices.Contains(coProfessors, professor) {
+				return ModelInput{}, fmt.Errorf("professor \"%v\" is listed more than once in the team of subject \"%v\"", input.Professors[professor].Name, rawInput.Subjects[rawEntry.Subject].Name)
+			}
+			coProfessors = append(coProfessors, professor)
+		}
+
 		// Find subject-professor
 		subjectProfessor, ok := lo.Find(subjectProfessors, func(subjectProfessor SubjectProfessor) bool {
 			return subjectProfessor.Subject == rawEntry.Subject && subjectProfessor.Professor == rawEntry.Professor
@@ -120,13 +294,17 @@ func processRawInput(rawInput rawModelInput) (ModelInput, error) {
 		// Initialize subject-professor if it does not exist
 		if !ok {
 			subjectProfessor = SubjectProfessor{
-				Id:        uint64(len(subjectProfessors)),
-				Subject:   rawEntry.Subject,
-				Professor: rawEntry.Professor,
+				Id:           uint64(len(subjectProfessors)),
+				Subject:      rawEntry.Subject,
+				Professor:    rawEntry.Professor,
+				CoProfessors: coProfessors,
 			}
 			subjectProfessors = append(subjectProfessors, subjectProfessor)
 		}
-		subjectProfessorName := fmt.Sprintf("%v~%v", rawInput.Subjects[subjectProfessor.Subject].Name, rawInput.Professors[subjectProfessor.Professor].Name)
+		subjectProfessorName := fmt.Sprintf("%v~%v", rawInput.Subjects[subjectProfessor.Subject].Name, teamName(input, subjectProfessor))
+		if !slices.Equal(subjectProfessor.CoProfessors, coProfessors) {
+			return ModelInput{}, fmt.Errorf("entries of the subject-professor \"%v\" must be taught by the same team", subjectProfessorName)
+		}
 		subjectProfessorKey := [2]uint64{subjectProfessor.Subject, subjectProfessor.Professor}
 
 		// Initialize associated-classes for subject-professor if it does not exist
@@ -144,7 +322,7 @@ func processRawInput(rawInput rawModelInput) (ModelInput, error) {
 			associatedClasses[subjectProfessorKey][class] = true
 			return false
 		}) {
-			return ModelInput{}, fmt.Errorf("groups associated to the same subject-professor \"%v\" must be disjoint sets: class \"%v\" is present in more than one group or group \"%v\" is not a set", subjectProfessorName, rawInput.Classes[conflictingClass].Name, lo.Map(rawEntry.Classes, func(class uint64, _ int) string { return rawInput.Classes[class].Name }))
+			return ModelInput{}, fmt.Errorf("groups associated to the same subject-professor \"%v\" must be disjoint sets: class \"%v\" is present in more than one group or group \"%v\" is not a set", subjectProfessorName, input.Classes[conflictingClass].Name, lo.Map(rawEntry.Classes, func(class uint64, _ int) string { return input.Classes[class].Name }))
 		}
 
 		//** Manage group
@@ -162,6 +340,14 @@ func processRawInput(rawInput rawModelInput) (ModelInput, error) {
 			groups = append(groups, group)
 		}
 
+		//** Manage weekly lessons
+		weeklyLessons := rawEntry.WeeklyLessons
+		if len(weeklyLessons) == 0 {
+			weeklyLessons = lo.RepeatBy(int(input.Weeks), func(_ int) uint64 { return rawEntry.Lessons })
+		} else if uint64(len(weeklyLessons)) != input.Weeks {
+			return ModelInput{}, fmt.Errorf("weekly lessons of \"%v\" must have one count per week (%d)", subjectProfessorName, input.Weeks)
+		}
+
 		//** Manage entry
 		entryKey := [2]uint64{subjectProfessor.Id, group.Id}
 		// Make sure that can only be one entry for each subject-professor and group
@@ -169,13 +355,51 @@ func processRawInput(rawInput rawModelInput) (ModelInput, error) {
 			return ModelInput{}, fmt.Errorf("duplicate entry for subject %d and group %d", subjectProfessor.Id, group.Id)
 		} else {
 			entry := Entry{
-				SubjectProfessor: subjectProfessor.Id,
-				Group:            group.Id,
-				Lessons:          rawEntry.Lessons,
-				Permissibility:   rawEntry.Permissibility,
-				Rooms:            rawEntry.Rooms,
+				SubjectProfessor:  subjectProfessor.Id,
+				Group:             group.Id,
+				Lessons:           lo.Sum(weeklyLessons),
+				WeeklyLessons:     weeklyLessons,
+				Permissibility:    tileWeeks(rawEntry.Permissibility, input.Weeks),
+				Rooms:             rawEntry.Rooms,
+				RequiredFeatures:  rawEntry.RequiredFeatures,
+				ForbiddenFeatures: rawEntry.ForbiddenFeatures,
+				SoftRoomStability: rawEntry.SoftRoomStability,
+				UseHomeRoom:       rawEntry.UseHomeRoom,
+				Tag:               rawEntry.Tag,
+			}
+
+			// Restrict the entry to the slots its classes are available at
+			var err error
+			if entry.Permissibility, err = classPermissibility(input, group, entry); err != nil {
+				return ModelInput{}, fmt.Errorf("\"%v\" is impossible: %v", subjectProfessorName, err)
+			}
+
+			if entry.RoomStability, ok = roomStabilities[rawEntry.RoomStability]; !ok {
+				return ModelInput{}, fmt.Errorf("unknown room stability \"%v\" for \"%v\": expected \"entry\" or \"day\"", rawEntry.RoomStability, subjectProfessorName)
+			}
+
+			// Teach the entry in its group's home room when it fits, otherwise fall back to the entry's rooms
+			if entry.UseHomeRoom {
+				homeRoom, err := groupHomeRoom(input, group)
+				if err != nil {
+					return ModelInput{}, fmt.Errorf("cannot use the home room for \"%v\": %v", subjectProfessorName, err)
+				}
+				if groupSize := lo.Sum(lo.Map(group.Classes, func(class uint64, _ int) uint64 { return input.Classes[class].Size })); input.Rooms[homeRoom].Capacity >= groupSize {
+					entry.Rooms = []uint64{homeRoom}
+				}
+			}
+
+			// Derive the allowed rooms from the features when they're not explicitly given (explicit rooms act as an override)
+			if len(entry.Rooms) == 0 {
+				entry.Rooms = featuredRooms(rawInput.Rooms, entry.RequiredFeatures, entry.ForbiddenFeatures)
+
+				groupSize := lo.Sum(lo.Map(group.Classes, func(class uint64, _ int) uint64 { return input.Classes[class].Size }))
+				if !lo.SomeBy(entry.Rooms, func(room uint64) bool { return rawInput.Rooms[room].Capacity >= groupSize }) {
+					return ModelInput{}, fmt.Errorf("there are no fitting rooms with features %v and without features %v for \"%v\" to %v", entry.RequiredFeatures, entry.ForbiddenFeatures, subjectProfessorName, lo.Map(group.Classes, func(class uint64, _ int) string { return input.Classes[class].Name }))
+				}
 			}
 			entries[entryKey] = entry
+			entryKeys[i] = entryKey
 		}
 	}
 
@@ -195,9 +419,426 @@ func processRawInput(rawInput rawModelInput) (ModelInput, error) {
 	input.Entries = entries
 	input.Curriculum = curriculum
 	input.GroupsGraph = buildGroupsGraph(groups)
+	input.Pools = lo.Map(rawPools, func(rawPool []int, _ int) [][2]uint64 {
+		return lo.Map(rawPool, func(i int, _ int) [2]uint64 { return entryKeys[i] })
+	})
+
+	//** Validate professors' loads
+	if err := validateLoads(input); err != nil {
+		return ModelInput{}, err
+	}
+
+	//** Manage pinned and forbidden assignments
+	if input.Pinned, err = processRawAssignments(rawInput.Pinned, input, true); err != nil {
+		return ModelInput{}, err
+	}
+	if input.Forbidden, err = processRawAssignments(rawInput.Forbidden, input, false); err != nil {
+		return ModelInput{}, err
+	}
+
+	//** Manage simultaneous entries
+	if input.Simultaneous, err = processRawSimultaneous(rawInput.Simultaneous, input); err != nil {
+		return ModelInput{}, err
+	}
+
 	return input, nil
 }
 
+func validateTravelTimes(input ModelInput) error {
+	if len(input.TravelTimes) == 0 {
+		return nil
+	}
+
+	buildings := len(input.Buildings)
+	if len(input.TravelTimes) != buildings || lo.SomeBy(input.TravelTimes, func(row []uint64) bool { return len(row) != buildings }) {
+		return fmt.Errorf("travel-times matrix must be of size %dx%d (one row and one column per building)", buildings, buildings)
+	} else if room, ok := lo.Find(input.Rooms, func(room Room) bool { return room.Building >= uint64(buildings) }); ok {
+		return fmt.Errorf("room \"%v\" refers to a non-existing building %d", room.Name, room.Building)
+	} else if periods := len(input.Professors[0].Availability); len(input.Breaks) != 0 && len(input.Breaks) != periods-1 {
+		return fmt.Errorf("there must be one break between each pair of consecutive periods: expected %d breaks but got %d", periods-1, len(input.Breaks))
+	}
+	return nil
+}
+
+// Validates the professors' preference matrices, returning copies of the professors where the impossible slots are marked as unavailable and the
+// preferred slots are derived from the graded preferences (the input's availability matrices are left untouched)
+func processPreferences(professors []Professor) ([]Professor, error) {
+	processed := slices.Clone(professors)
+	for i, professor := range processed {
+		if len(professor.Preferred) != 0 && (len(professor.Preferred) != len(professor.Availability) || lo.SomeBy(professor.Preferred, func(row []bool) bool { return len(row) != len(professor.Availability[0]) })) {
+			return nil, fmt.Errorf("preferred slots of professor \"%v\" must have the same size as its availability matrix", professor.Name)
+		}
+
+		if len(professor.Preferences) == 0 {
+			continue
+		} else if len(professor.Preferred) != 0 {
+			return nil, fmt.Errorf("professor \"%v\" cannot have both preferred slots and a preference matrix, since the former are derived from the latter", professor.Name)
+		} else if len(professor.Preferences) != len(professor.Availability) || lo.SomeBy(professor.Preferences, func(row []uint64) bool { return len(row) != len(professor.Availability[0]) }) {
+			return nil, fmt.Errorf("preference matrix of professor \"%v\" must have the same size as its availability matrix", professor.Name)
+		}
+
+		availability := lo.Map(professor.Availability, func(row []bool, _ int) []bool { return slices.Clone(row) })
+		preferred := make([][]bool, len(professor.Preferences))
+		for period, row := range professor.Preferences {
+			preferred[period] = make([]bool, len(row))
+			for day, preference := range row {
+				if preference > PreferencePreferred {
+					return nil, fmt.Errorf("invalid preference %d of professor \"%v\" at period %d and day %d: preferences must be between %d and %d", preference, professor.Name, period, day, PreferenceImpossible, PreferencePreferred)
+				} else if preference == PreferenceImpossible {
+					availability[period][day] = false
+				}
+				preferred[period][day] = preference == PreferencePreferred
+			}
+		}
+		processed[i].Availability, processed[i].Preferred = availability, preferred
+	}
+	return processed, nil
+}
+
+// Validates that the rooms' and classes' availability matrices (if given) are shaped like the professors' ones
+func validateAvailability(input ModelInput) error {
+	periods, days := len(input.Professors[0].Availability), len(input.Professors[0].Availability[0])
+	invalid := func(availability [][]bool) bool {
+		return len(availability) != 0 && (len(availability) != periods || lo.SomeBy(availability, func(row []bool) bool { return len(row) != days }))
+	}
+
+	for _, room := range input.Rooms {
+		if invalid(room.Availability) {
+			return fmt.Errorf("availability of room \"%v\" must be of size %dx%d (one row per period and one column per day)", room.Name, periods, days)
+		}
+	}
+	for _, class := range input.Classes {
+		if invalid(class.Availability) {
+			return fmt.Errorf("availability of class \"%v\" must be of size %dx%d (one row per period and one column per day)", class.Name, periods, days)
+		}
+	}
+	return nil
+}
+
+// Returns the permissibility restricted to the slots every class of the group is available at, failing if the entry's lessons of some week no
+// longer fit in the remaining days of that week (each lesson of an entry is taught on a different day)
+func classPermissibility(input ModelInput, group Group, entry Entry) ([][]bool, error) {
+	days := daysPerWeek(input)
+	permittedDays := func(permissibility [][]bool, week uint64) uint64 {
+		permitted := make(map[uint64]bool)
+		for _, row := range permissibility {
+			for day := week * days; day < (week+1)*days && day < uint64(len(row)); day++ {
+				if row[day] {
+					permitted[day] = true
+				}
+			}
+		}
+		return uint64(len(permitted))
+	}
+
+	permissibility := make([][]bool, len(entry.Permissibility))
+	for period, row := range entry.Permissibility {
+		permissibility[period] = make([]bool, len(row))
+		for day, permitted := range row {
+			permissibility[period][day] = permitted && lo.EveryBy(group.Classes, func(class uint64) bool {
+				availability := input.Classes[class].Availability
+				return len(availability) == 0 || availability[period][day]
+			})
+		}
+	}
+
+	for week := range input.Weeks {
+		if lessons := lessonsOfWeek(entry, week); permittedDays(permissibility, week) < lessons && permittedDays(entry.Permissibility, week) >= lessons {
+			return nil, fmt.Errorf("the availability of classes %v leaves fewer days (%d) than lessons (%d) in week %d", lo.Map(group.Classes, func(class uint64, _ int) string { return input.Classes[class].Name }), permittedDays(permissibility, week), lessons, week)
+		}
+	}
+	return permissibility, nil
+}
+
+// Returns the classes of the raw input, where classes without a home room get Any
+func processRawClasses(rawClasses []rawClass) []Class {
+	return lo.Map(rawClasses, func(rawClass rawClass, _ int) Class {
+		class := Class{Id: rawClass.Id, Name: rawClass.Name, Size: rawClass.Size, HomeRoom: Any, Availability: rawClass.Availability}
+		if rawClass.HomeRoom != nil {
+			class.HomeRoom = *rawClass.HomeRoom
+		}
+		return class
+	})
+}
+
+// Returns the home room shared by the group's classes, which must exist and be the same for every class
+func groupHomeRoom(input ModelInput, group Group) (uint64, error) {
+	homeRoom := Any
+	for _, class := range group.Classes {
+		switch classHomeRoom := input.Classes[class].HomeRoom; {
+		case classHomeRoom == Any:
+			return 0, fmt.Errorf("class \"%v\" has no home room", input.Classes[class].Name)
+		case classHomeRoom >= uint64(len(input.Rooms)):
+			return 0, fmt.Errorf("class \"%v\" refers to a non-existing home room %d", input.Classes[class].Name, classHomeRoom)
+		case homeRoom != Any && classHomeRoom != homeRoom:
+			return 0, fmt.Errorf("classes %v have conflicting home rooms", lo.Map(group.Classes, func(class uint64, _ int) string { return input.Classes[class].Name }))
+		}
+		homeRoom = input.Classes[class].HomeRoom
+	}
+	return homeRoom, nil
+}
+
+// Returns the rooms providing every required feature and none of the forbidden ones
+func featuredRooms(rooms []Room, requiredFeatures, forbiddenFeatures []string) []uint64 {
+	featured := make([]uint64, 0)
+	for _, room := range rooms {
+		if lo.Every(room.Features, requiredFeatures) && !lo.Some(room.Features, forbiddenFeatures) {
+			featured = append(featured, room.Id)
+		}
+	}
+	return featured
+}
+
+func processRawAssignments(rawAssignments []rawAssignment, input ModelInput, pinned bool) ([]Assignment, error) {
+	assignments := make([]Assignment, 0, len(rawAssignments))
+	for _, rawAssignment := range rawAssignments {
+		// Find the entry the assignment refers to
+		entryKey, ok := findEntry(input, rawAssignment.Subject, rawAssignment.Professor, rawAssignment.Classes)
+		if !ok {
+			return nil, fmt.Errorf("assignment refers to a non-existing entry: subject %d, professor %d and classes %v", rawAssignment.Subject, rawAssignment.Professor, rawAssignment.Classes)
+		}
+		entry := input.Entries[entryKey]
+		subjectProfessor := input.SubjectProfessors[entryKey[0]]
+
+		assignment := Assignment{
+			SubjectProfessor: entryKey[0],
+			Group:            entryKey[1],
+			Lesson:           Any,
+			Day:              Day(input, rawAssignment.Week, rawAssignment.Day),
+			Period:           rawAssignment.Period,
+			Room:             Any,
+		}
+		if rawAssignment.Lesson != nil {
+			assignment.Lesson = *rawAssignment.Lesson
+		}
+		if rawAssignment.Room != nil {
+			assignment.Room = *rawAssignment.Room
+		}
+
+		// Validate the assignment's attributes
+		subjectProfessorName := fmt.Sprintf("%v~%v", input.Subjects[subjectProfessor.Subject].Name, teamName(input, subjectProfessor))
+		if assignment.Period >= uint64(len(entry.Permissibility)) || assignment.Day >= uint64(len(entry.Permissibility[assignment.Period])) || rawAssignment.Day >= daysPerWeek(input) {
+			return nil, fmt.Errorf("assignment of \"%v\" is out of range: period %d, day %d and week %d", subjectProfessorName, assignment.Period, rawAssignment.Day, rawAssignment.Week)
+		} else if assignment.Lesson != Any && assignment.Lesson >= entry.Lessons {
+			return nil, fmt.Errorf("assignment of \"%v\" refers to lesson %d but the entry only has %d lessons", subjectProfessorName, assignment.Lesson, entry.Lessons)
+		} else if assignment.Room != Any && assignment.Room >= uint64(len(input.Rooms)) {
+			return nil, fmt.Errorf("assignment of \"%v\" refers to a non-existing room %d", subjectProfessorName, assignment.Room)
+		}
+
+		// Make sure pinned assignments do not contradict the entry's permissibility nor the professor's availability
+		if pinned && (!entry.Permissibility[assignment.Period][assignment.Day] || lo.SomeBy(subjectProfessor.Team(), func(professor uint64) bool {
+			return !input.Professors[professor].Availability[assignment.Period][assignment.Day]
+		})) {
+			return nil, fmt.Errorf("pinned assignment of \"%v\" at period %d and day %d is not permitted or the professor is not available", subjectProfessorName, assignment.Period, assignment.Day)
+		}
+
+		// Make sure pinned rooms can hold the entry's lessons, otherwise the pin could never be satisfied
+		if pinned && assignment.Room != Any {
+			room := input.Rooms[assignment.Room]
+			group := input.Groups[entryKey[1]]
+			groupSize := lo.Sum(lo.Map(group.Classes, func(class uint64, _ int) uint64 { return input.Classes[class].Size }))
+			if !slices.Contains(entry.Rooms, assignment.Room) {
+				return nil, fmt.Errorf("pinned assignment of \"%v\" refers to room \"%v\", which is not among the entry's rooms", entryName(input, entryKey), room.Name)
+			} else if room.Capacity < groupSize {
+				return nil, fmt.Errorf("pinned assignment of \"%v\" refers to room \"%v\", which seats %d but the group has %d students", entryName(input, entryKey), room.Name, room.Capacity, groupSize)
+			} else if len(room.Availability) != 0 && !room.Availability[assignment.Period][assignment.Day] {
+				return nil, fmt.Errorf("pinned assignment of \"%v\" refers to room \"%v\", which is not available at period %d and day %d", entryName(input, entryKey), room.Name, assignment.Period, assignment.Day)
+			}
+		}
+
+		assignments = append(assignments, assignment)
+	}
+	return assignments, nil
+}
+
+func processRawSimultaneous(rawSimultaneous [][]rawEntryReference, input ModelInput) ([][][2]uint64, error) {
+	simultaneous := make([][][2]uint64, 0, len(rawSimultaneous))
+	for _, rawReferences := range rawSimultaneous {
+		entryKeys := make([][2]uint64, 0, len(rawReferences))
+		for _, reference := range rawReferences {
+			entryKey, ok := findEntry(input, reference.Subject, reference.Professor, reference.Classes)
+			if !ok {
+				return nil, fmt.Errorf("simultaneous group refers to a non-existing entry: subject %d, professor %d and classes %v", reference.Subject, reference.Professor, reference.Classes)
+			} else if slices.Contains(entryKeys, entryKey) {
+				return nil, fmt.Errorf("simultaneous group refers more than once to the entry: subject %d, professor %d and classes %v", reference.Subject, reference.Professor, reference.Classes)
+			} else if _, ok := candidatePool(input, entryKey); ok {
+				return nil, fmt.Errorf("simultaneous group refers to the candidate entry \"%v\", whose professor is chosen by the solver", entryName(input, entryKey))
+			}
+			entryKeys = append(entryKeys, entryKey)
+		}
+
+		// Make sure every entry in the group has the same number of lessons, since lessons are paired by index
+		if len(entryKeys) > 0 && lo.SomeBy(entryKeys, func(entryKey [2]uint64) bool {
+			return input.Entries[entryKey].Lessons != input.Entries[entryKeys[0]].Lessons
+		}) {
+			return nil, fmt.Errorf("entries of a simultaneous group must have the same number of lessons: %v", lo.Map(entryKeys, func(entryKey [2]uint64, _ int) string {
+				return entryName(input, entryKey)
+			}))
+		}
+
+		// Make sure no two entries in the group share a class or a professor, since they could never be taught at the same time
+		for i, entryKey1 := range entryKeys {
+			for _, entryKey2 := range entryKeys[i+1:] {
+				team1, team2 := input.SubjectProfessors[entryKey1[0]].Team(), input.SubjectProfessors[entryKey2[0]].Team()
+				if input.GroupsGraph[entryKey1[1]][entryKey2[1]] || lo.Some(team1, team2) {
+					return nil, fmt.Errorf("entries \"%v\" and \"%v\" of a simultaneous group share a class or a professor, thus they cannot be taught at the same time", entryName(input, entryKey1), entryName(input, entryKey2))
+				}
+			}
+		}
+		simultaneous = append(simultaneous, entryKeys)
+	}
+	return simultaneous, nil
+}
+
+// Repeats the (weekly) availability and preference matrices of professors, rooms and classes over every week of the input
+func expandWeeks(input *ModelInput) {
+	for i := range input.Professors {
+		input.Professors[i].Availability = tileWeeks(input.Professors[i].Availability, input.Weeks)
+		input.Professors[i].Preferred = tileWeeks(input.Professors[i].Preferred, input.Weeks)
+		input.Professors[i].Preferences = tileWeeks(input.Professors[i].Preferences, input.Weeks)
+	}
+	for i := range input.Rooms {
+		input.Rooms[i].Availability = tileWeeks(input.Rooms[i].Availability, input.Weeks)
+	}
+	for i := range input.Classes {
+		input.Classes[i].Availability = tileWeeks(input.Classes[i].Availability, input.Weeks)
+	}
+}
+
+// Returns the matrix (i.e. one row per period and one column per day) repeated column-wise once per week
+func tileWeeks[T any](matrix [][]T, weeks uint64) [][]T {
+	if weeks <= 1 || len(matrix) == 0 {
+		return matrix
+	}
+	tiled := make([][]T, len(matrix))
+	for period, row := range matrix {
+		tiled[period] = make([]T, 0, uint64(len(row))*weeks)
+		for range weeks {
+			tiled[period] = append(tiled[period], row...)
+		}
+	}
+	return tiled
+}
+
+// Returns the number of days per week of the input
+func daysPerWeek(input ModelInput) uint64 {
+	return uint64(len(input.Professors[0].Availability[0])) / max(input.Weeks, 1)
+}
+
+// Returns the week the day belongs to along with the day within that week
+func WeekDay(input ModelInput, day uint64) (week, weekDay uint64) {
+	return day / daysPerWeek(input), day % daysPerWeek(input)
+}
+
+// Returns the day numbered after the given week and day within that week
+func Day(input ModelInput, week, weekDay uint64) uint64 {
+	return week*daysPerWeek(input) + weekDay
+}
+
+// Returns the number of lessons of the entry taught in the given week
+func lessonsOfWeek(entry Entry, week uint64) uint64 {
+	if len(entry.WeeklyLessons) == 0 {
+		return entry.Lessons
+	}
+	return entry.WeeklyLessons[week]
+}
+
+// Expands every raw entry with candidate professors into one raw entry per candidate, returning the expanded raw entries along with the indexes
+// of the candidate ones grouped by the raw entry they come from
+func expandCandidates(rawEntries []rawEntry, input ModelInput) ([]rawEntry, [][]int, error) {
+	expanded, pools := make([]rawEntry, 0, len(rawEntries)), make([][]int, 0)
+	for _, rawEntry := range rawEntries {
+		if len(rawEntry.Candidates) == 0 {
+			expanded = append(expanded, rawEntry)
+			continue
+		} else if len(rawEntry.Professors) > 0 {
+			return nil, nil, fmt.Errorf("entry of subject \"%v\" cannot have both a team and candidate professors", input.Subjects[rawEntry.Subject].Name)
+		}
+
+		pool := make([]int, 0, len(rawEntry.Candidates))
+		for i, candidate := range rawEntry.Candidates {
+			if candidate >= uint64(len(input.Professors)) {
+				return nil, nil, fmt.Errorf("entry refers to a non-existing candidate professor %d", candidate)
+			} else if slices.Contains(rawEntry.Candidates[:i], candidate) {
+				return nil, nil, fmt.Errorf("professor \"%v\" is listed more than once among the candidates of subject \"%v\"", input.Professors[candidate].Name, input.Subjects[rawEntry.Subject].Name)
+			}
+			candidateEntry := rawEntry
+			candidateEntry.Professor, candidateEntry.Candidates = candidate, nil
+			candidateEntry.Classes = slices.Clone(rawEntry.Classes)
+			pool = append(pool, len(expanded))
+			expanded = append(expanded, candidateEntry)
+		}
+		pools = append(pools, pool)
+	}
+	return expanded, pools, nil
+}
+
+// Returns the index of the pool the entry is a candidate entry of
+func candidatePool(input ModelInput, entryKey [2]uint64) (int, bool) {
+	pool := slices.IndexFunc(input.Pools, func(pool [][2]uint64) bool { return slices.Contains(pool, entryKey) })
+	return pool, pool >= 0
+}
+
+// Returns the number of lessons the professor teaches in the given week regardless of the solver's choices (i.e. those of its entries that are not
+// candidate ones)
+func fixedLoad(input ModelInput, professor, week uint64) uint64 {
+	load := uint64(0)
+	for entryKey, entry := range input.Entries {
+		if _, ok := candidatePool(input, entryKey); !ok && slices.Contains(input.SubjectProfessors[entryKey[0]].Team(), professor) {
+			load += lessonsOfWeek(entry, week)
+		}
+	}
+	return load
+}
+
+// Validates that no professor's fixed load exceeds its maximum load in any week
+func validateLoads(input ModelInput) error {
+	for professor, value := range input.Professors {
+		if overloaded, ok := lo.Find(lo.Range(int(input.Weeks)), func(week int) bool {
+			return value.MaxLoad > 0 && fixedLoad(input, uint64(professor), uint64(week)) > value.MaxLoad
+		}); ok {
+			return fmt.Errorf("professor \"%v\" teaches %d lessons in week %d, exceeding its maximum load (%d)", value.Name, fixedLoad(input, uint64(professor), uint64(overloaded)), overloaded, value.MaxLoad)
+		} else if value.MinLoad > 0 && value.MaxLoad > 0 && value.MinLoad > value.MaxLoad {
+			return fmt.Errorf("the minimum load (%d) of professor \"%v\" exceeds its maximum load (%d)", value.MinLoad, value.Name, value.MaxLoad)
+		}
+	}
+	return nil
+}
+
+// Returns a human-readable name of the entry (e.g. "Logica~Luciano to [CC-111 CC-112]")
+func entryName(input ModelInput, entryKey [2]uint64) string {
+	subjectProfessor := input.SubjectProfessors[entryKey[0]]
+	classes := lo.Map(input.Groups[entryKey[1]].Classes, func(class uint64, _ int) string { return input.Classes[class].Name })
+	return fmt.Sprintf("%v~%v to %v", input.Subjects[subjectProfessor.Subject].Name, teamName(input, subjectProfessor), classes)
+}
+
+// Returns the names of the subject-professor's team joined by "+" (e.g. "Luciano+Dalianys")
+func teamName(input ModelInput, subjectProfessor SubjectProfessor) string {
+	return strings.Join(lo.Map(subjectProfessor.Team(), func(professor uint64, _ int) string { return input.Professors[professor].Name }), "+")
+}
+
+// Returns the key of the entry associated to the subject, professor and classes
+func findEntry(input ModelInput, subject, professor uint64, classes []uint64) ([2]uint64, bool) {
+	subjectProfessor, ok := lo.Find(input.SubjectProfessors, func(subjectProfessor SubjectProfessor) bool {
+		return subjectProfessor.Subject == subject && subjectProfessor.Professor == professor
+	})
+	if !ok {
+		return [2]uint64{}, false
+	}
+
+	classes = slices.Sorted(slices.Values(classes))
+	group, ok := lo.Find(input.Groups, func(group Group) bool {
+		return slices.Equal(group.Classes, classes)
+	})
+	if !ok {
+		return [2]uint64{}, false
+	}
+
+	entryKey := [2]uint64{subjectProfessor.Id, group.Id}
+	_, ok = input.Entries[entryKey]
+	return entryKey, ok
+}
+
 func buildGroupsGraph(groups []Group) [][]bool {
 	groupsGraph := make([][]bool, len(groups))
 
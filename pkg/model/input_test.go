@@ -0,0 +1,599 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoomsDerivedFromFeatures(t *testing.T) {
+	rooms := []Room{
+		{Id: 0, Name: "Aula 1", Capacity: 50, Features: []string{"projector"}},
+		{Id: 1, Name: "Lab 1", Capacity: 50, Features: []string{"projector", "computers"}},
+		{Id: 2, Name: "Lab 2", Capacity: 10, Features: []string{"projector", "computers"}},
+		{Id: 3, Name: "Aula 2", Capacity: 50, Features: []string{"projector", "computers", "accessible"}},
+		{Id: 4, Name: "Lab 3", Capacity: 10, Features: []string{"computers"}},
+	}
+	scenarios := []struct {
+		rooms               []uint64 // Explicit rooms, which act as an override
+		required, forbidden []string
+		expected            []uint64 // Nil if no room fits CC-111
+	}{
+		{nil, []string{"projector", "computers"}, []string{"accessible"}, []uint64{1, 2}}, // Lab 2 is kept, since Lab 1 fits CC-111
+		{nil, []string{"accessible"}, nil, []uint64{3}},
+		{nil, []string{"computers"}, []string{"projector"}, nil}, // Only Lab 3, which is too small
+		{[]uint64{0}, []string{"computers"}, nil, []uint64{0}},
+	}
+
+	for _, scenario := range scenarios {
+		// Arrange
+		rawInput := rawModelInput{ // Luciano teaches Logica to CC-111 once, in a single slot
+			Subjects:   []Subject{{Id: 0, Name: "Logica"}},
+			Professors: []Professor{{Id: 0, Name: "Luciano", Availability: [][]bool{{true}}}},
+			Classes:    []rawClass{{Id: 0, Name: "CC-111", Size: 30}},
+			Rooms:      rooms,
+			Entries: []rawEntry{{
+				Subject: 0, Professor: 0, Classes: []uint64{0}, Lessons: 1, Permissibility: [][]bool{{true}},
+				Rooms: scenario.rooms, RequiredFeatures: scenario.required, ForbiddenFeatures: scenario.forbidden,
+			}},
+		}
+
+		// Act
+		input, err := processRawInput(rawInput)
+
+		// Assert
+		if scenario.expected == nil {
+			assert.NotNil(t, err, "scenario = %v", scenario)
+			continue
+		}
+		assert.Nil(t, err)
+		assert.Equal(t, scenario.expected, input.Entries[[2]uint64{0, 0}].Rooms, "scenario = %v", scenario)
+	}
+}
+
+func TestPreferences(t *testing.T) {
+	scenarios := []struct {
+		preferences  [][]uint64
+		preferred    [][]bool
+		availability [][]bool // Nil if the preferences are invalid
+	}{
+		{[][]uint64{{PreferencePreferred, PreferenceImpossible}, {PreferenceDisliked, PreferenceNeutral}}, nil, [][]bool{{true, false}, {true, true}}},
+		{[][]uint64{{PreferencePreferred, PreferencePreferred + 1}, {PreferenceNeutral, PreferenceNeutral}}, nil, nil},                               // Unknown grade
+		{[][]uint64{{PreferencePreferred, PreferencePreferred}}, nil, nil},                                                                           // Wrong size
+		{[][]uint64{{PreferencePreferred, PreferenceNeutral}, {PreferenceNeutral, PreferenceNeutral}}, [][]bool{{true, false}, {false, false}}, nil}, // Both matrices
+	}
+
+	for _, scenario := range scenarios {
+		// Arrange
+		rawInput := rawModelInput{ // Luciano teaches Logica to CC-111 once on one of two days with two periods each
+			Subjects: []Subject{{Id: 0, Name: "Logica"}},
+			Professors: []Professor{
+				{Id: 0, Name: "Luciano", Availability: [][]bool{{true, true}, {true, true}}, Preferences: scenario.preferences, Preferred: scenario.preferred},
+			},
+			Classes: []rawClass{{Id: 0, Name: "CC-111", Size: 30}},
+			Rooms:   []Room{{Id: 0, Name: "Aula 1", Capacity: 50}},
+			Entries: []rawEntry{
+				{Subject: 0, Professor: 0, Classes: []uint64{0}, Lessons: 1, Permissibility: [][]bool{{true, true}, {true, true}}, Rooms: []uint64{0}},
+			},
+		}
+
+		// Act
+		input, err := processRawInput(rawInput)
+
+		// Assert
+		if scenario.availability == nil {
+			assert.NotNil(t, err, "preferences = %v", scenario.preferences)
+			continue
+		}
+		assert.Nil(t, err)
+		assert.Equal(t, scenario.availability, input.Professors[0].Availability)
+		assert.Equal(t, [][]bool{{true, false}, {false, false}}, input.Professors[0].Preferred)
+		assert.Equal(t, [][]bool{{true, true}, {true, true}}, rawInput.Professors[0].Availability) // The raw input is left untouched
+		assert.Nil(t, rawInput.Professors[0].Preferred)
+	}
+}
+
+func TestPreferredShape(t *testing.T) {
+	scenarios := []struct {
+		preferred [][]bool
+		valid     bool
+	}{
+		{nil, true},
+		{[][]bool{{true, false}, {false, true}}, true},
+		{[][]bool{{true, false}}, false},
+		{[][]bool{{true, false}, {false}}, false},
+	}
+
+	for _, scenario := range scenarios {
+		// Arrange
+		rawInput := rawModelInput{ // Luciano teaches Logica to CC-111 once on one of two days with two periods each
+			Subjects:   []Subject{{Id: 0, Name: "Logica"}},
+			Professors: []Professor{{Id: 0, Name: "Luciano", Availability: [][]bool{{true, true}, {true, true}}, Preferred: scenario.preferred}},
+			Classes:    []rawClass{{Id: 0, Name: "CC-111", Size: 30}},
+			Rooms:      []Room{{Id: 0, Name: "Aula 1", Capacity: 50}},
+			Entries: []rawEntry{
+				{Subject: 0, Professor: 0, Classes: []uint64{0}, Lessons: 1, Permissibility: [][]bool{{true, true}, {true, true}}, Rooms: []uint64{0}},
+			},
+		}
+
+		// Act
+		_, err := processRawInput(rawInput)
+
+		// Assert
+		assert.Equal(t, scenario.valid, err == nil, "preferred = %v", scenario.preferred)
+	}
+}
+
+func TestParseRoomStability(t *testing.T) {
+	scenarios := []struct {
+		name     string
+		expected RoomStability
+		valid    bool
+	}{
+		{"", RoomStabilityNone, true},
+		{"entry", RoomStabilityEntry, true},
+		{"day", RoomStabilityDay, true},
+		{"week", RoomStabilityNone, false}, // Unknown scope
+	}
+
+	for _, scenario := range scenarios {
+		// Arrange
+		rawInput := rawModelInput{ // Luciano teaches Logica to CC-111 once, in a single slot
+			Subjects:   []Subject{{Id: 0, Name: "Logica"}},
+			Professors: []Professor{{Id: 0, Name: "Luciano", Availability: [][]bool{{true}}}},
+			Classes:    []rawClass{{Id: 0, Name: "CC-111", Size: 30}},
+			Rooms:      []Room{{Id: 0, Name: "Aula 1", Capacity: 50}},
+			Entries: []rawEntry{
+				{Subject: 0, Professor: 0, Classes: []uint64{0}, Lessons: 1, Permissibility: [][]bool{{true}}, Rooms: []uint64{0}, RoomStability: scenario.name},
+			},
+		}
+
+		// Act
+		input, err := processRawInput(rawInput)
+
+		// Assert
+		if !scenario.valid {
+			assert.NotNil(t, err, "room stability = %q", scenario.name)
+			continue
+		}
+		assert.Nil(t, err)
+		assert.Equal(t, scenario.expected, input.Entries[[2]uint64{0, 0}].RoomStability)
+	}
+}
+
+func TestHomeRoom(t *testing.T) {
+	scenarios := []struct {
+		homeRooms [2]*uint64 // Home rooms of CC-111 and CC-112
+		capacity  uint64     // Capacity of Aula 1
+		expected  []uint64   // Nil if the home room cannot be used
+	}{
+		{[2]*uint64{lo.ToPtr(uint64(1)), lo.ToPtr(uint64(1))}, 60, []uint64{1}},
+		{[2]*uint64{lo.ToPtr(uint64(1)), lo.ToPtr(uint64(1))}, 50, []uint64{0, 1}}, // Fall back to the entry's rooms when the home room does not fit
+		{[2]*uint64{lo.ToPtr(uint64(0)), lo.ToPtr(uint64(1))}, 60, nil},            // Conflicting home rooms
+		{[2]*uint64{lo.ToPtr(uint64(0)), nil}, 60, nil},                            // Missing home room
+		{[2]*uint64{lo.ToPtr(uint64(2)), lo.ToPtr(uint64(2))}, 60, nil},            // Non-existing home room
+	}
+
+	for _, scenario := range scenarios {
+		// Arrange
+		rawInput := rawModelInput{ // Luciano teaches Logica to CC-111 and CC-112 together, in a single slot
+			Subjects:   []Subject{{Id: 0, Name: "Logica"}},
+			Professors: []Professor{{Id: 0, Name: "Luciano", Availability: [][]bool{{true}}}},
+			Classes: []rawClass{
+				{Id: 0, Name: "CC-111", Size: 30, HomeRoom: scenario.homeRooms[0]},
+				{Id: 1, Name: "CC-112", Size: 30, HomeRoom: scenario.homeRooms[1]},
+			},
+			Rooms: []Room{{Id: 0, Name: "Aula Magna", Capacity: 100}, {Id: 1, Name: "Aula 1", Capacity: scenario.capacity}},
+			Entries: []rawEntry{
+				{Subject: 0, Professor: 0, Classes: []uint64{0, 1}, Lessons: 1, Permissibility: [][]bool{{true}}, Rooms: []uint64{0, 1}, UseHomeRoom: true},
+			},
+		}
+
+		// Act
+		input, err := processRawInput(rawInput)
+
+		// Assert
+		if scenario.expected == nil {
+			assert.NotNil(t, err, "scenario = %v", scenario)
+			continue
+		}
+		assert.Nil(t, err)
+		assert.Equal(t, scenario.expected, input.Entries[[2]uint64{0, 0}].Rooms)
+	}
+}
+
+func TestRoomAvailabilityShape(t *testing.T) {
+	scenarios := []struct {
+		availability [][]bool
+		valid        bool
+	}{
+		{nil, true}, // Always available
+		{[][]bool{{true, true}, {true, false}}, true},
+		{[][]bool{{true, true}}, false},   // Missing period
+		{[][]bool{{true}, {true}}, false}, // Missing day
+	}
+
+	for _, scenario := range scenarios {
+		// Arrange
+		rawInput := rawModelInput{ // Luciano teaches Logica to CC-111 once in Aula 1, on one of two days with two periods each
+			Subjects:   []Subject{{Id: 0, Name: "Logica"}},
+			Professors: []Professor{{Id: 0, Name: "Luciano", Availability: [][]bool{{true, true}, {true, true}}}},
+			Classes:    []rawClass{{Id: 0, Name: "CC-111", Size: 30}},
+			Rooms:      []Room{{Id: 0, Name: "Aula 1", Capacity: 50, Availability: scenario.availability}},
+			Entries: []rawEntry{
+				{Subject: 0, Professor: 0, Classes: []uint64{0}, Lessons: 1, Permissibility: [][]bool{{true, true}, {true, true}}, Rooms: []uint64{0}},
+			},
+		}
+
+		// Act
+		_, err := processRawInput(rawInput)
+
+		// Assert
+		assert.Equal(t, scenario.valid, err == nil, "availability = %v", scenario.availability)
+	}
+}
+
+func TestClassAvailability(t *testing.T) {
+	unavailableOnDay1 := [][]bool{{true, false}, {true, false}}
+	scenarios := []struct {
+		availability   [][]bool // Availability of CC-111
+		permissibility [][]bool
+		lessons        uint64
+		expected       [][]bool // Nil if the entry's lessons no longer fit in or the availability is invalid
+	}{
+		{unavailableOnDay1, [][]bool{{false, true}, {true, true}}, 1, [][]bool{{false, false}, {true, false}}},
+		{unavailableOnDay1, [][]bool{{true, true}, {true, true}}, 2, nil},                        // Both lessons cannot be taught on day 0
+		{nil, [][]bool{{true, false}, {true, false}}, 2, [][]bool{{true, false}, {true, false}}}, // The permissibility alone is left to the solver
+		{[][]bool{{true, true}, {false, true}}, [][]bool{{true, true}, {true, true}}, 2, [][]bool{{true, true}, {false, true}}},
+		{[][]bool{{true, true}}, [][]bool{{true, true}, {true, true}}, 1, nil}, // Wrong size
+	}
+
+	for _, scenario := range scenarios {
+		// Arrange
+		rawInput := rawModelInput{ // Luciano teaches Logica to CC-111 on two days with two periods each
+			Subjects:   []Subject{{Id: 0, Name: "Logica"}},
+			Professors: []Professor{{Id: 0, Name: "Luciano", Availability: [][]bool{{true, true}, {true, true}}}},
+			Classes:    []rawClass{{Id: 0, Name: "CC-111", Size: 30, Availability: scenario.availability}},
+			Rooms:      []Room{{Id: 0, Name: "Aula 1", Capacity: 50}},
+			Entries: []rawEntry{
+				{Subject: 0, Professor: 0, Classes: []uint64{0}, Lessons: scenario.lessons, Permissibility: scenario.permissibility, Rooms: []uint64{0}},
+			},
+		}
+
+		// Act
+		input, err := processRawInput(rawInput)
+
+		// Assert
+		if scenario.expected == nil {
+			assert.NotNil(t, err, "scenario = %v", scenario)
+			continue
+		}
+		assert.Nil(t, err)
+		assert.Equal(t, scenario.expected, input.Entries[[2]uint64{0, 0}].Permissibility, "scenario = %v", scenario)
+	}
+}
+
+func TestTeamTeaching(t *testing.T) {
+	// Arrange
+	rawInput := teamTeachingRawInput()
+	rawInput.Professors[0].Availability = [][]bool{{true, true}, {false, true}}
+
+	// Act
+	input, err := processRawInput(rawInput)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, []uint64{1, 0}, input.SubjectProfessors[0].Team())
+	evaluator := newPredicateEvaluator(input, 0)
+	assert.True(t, evaluator.SameProfessor(0, 1))          // Dalianys teaches both subjects
+	assert.False(t, evaluator.ProfessorAvailable(0, 0, 1)) // Luciano is not available at period 1 on day 0
+	assert.True(t, evaluator.ProfessorAvailable(0, 1, 1))
+}
+
+func TestInvalidTeamTeaching(t *testing.T) {
+	scenarios := [][2][]uint64{ // Teams of Logica for CC-111 and CC-112
+		{{0, 0}, nil}, // Repeated professor
+		{{0, 2}, nil}, // Non-existing professor
+		{{0, 1}, {0}}, // Same subject-professor with different teams
+	}
+
+	for _, teams := range scenarios {
+		// Arrange
+		rawInput := teamTeachingRawInput()
+		rawInput.Entries[1].Subject, rawInput.Entries[1].Professor = 0, 0 // Luciano also teaches Logica to CC-112
+		rawInput.Entries[0].Professors, rawInput.Entries[1].Professors = teams[0], teams[1]
+
+		// Act
+		_, err := processRawInput(rawInput)
+
+		// Assert
+		assert.NotNil(t, err, "teams = %v", teams)
+	}
+}
+
+// Returns a raw input where Dalianys leads Luciano teaching Logica to CC-111 in Aula 1 and teaches Algebra to CC-112 in Aula 2 alone, once each on
+// one of two days with two periods each
+func teamTeachingRawInput() rawModelInput {
+	availability := func() [][]bool { return [][]bool{{true, true}, {true, true}} }
+	return rawModelInput{
+		Subjects: []Subject{{Id: 0, Name: "Logica"}, {Id: 1, Name: "Algebra"}},
+		Professors: []Professor{
+			{Id: 0, Name: "Luciano", Availability: availability()},
+			{Id: 1, Name: "Dalianys", Availability: availability()},
+		},
+		Classes: []rawClass{{Id: 0, Name: "CC-111", Size: 30}, {Id: 1, Name: "CC-112", Size: 30}},
+		Rooms:   []Room{{Id: 0, Name: "Aula 1", Capacity: 50}, {Id: 1, Name: "Aula 2", Capacity: 50}},
+		Entries: []rawEntry{
+			{Subject: 0, Professors: []uint64{1, 0}, Classes: []uint64{0}, Lessons: 1, Permissibility: availability(), Rooms: []uint64{0}},
+			{Subject: 1, Professor: 1, Classes: []uint64{1}, Lessons: 1, Permissibility: availability(), Rooms: []uint64{1}},
+		},
+	}
+}
+
+func TestCandidateProfessors(t *testing.T) {
+	// Arrange
+	rawInput := candidatesRawInput()
+
+	// Act
+	input, err := processRawInput(rawInput)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Len(t, input.Pools, 1)
+	assert.Len(t, input.Entries, 3) // Algebra is expanded into one candidate entry per professor
+	assert.ElementsMatch(t, []uint64{0, 1}, lo.Map(input.Pools[0], func(entryKey [2]uint64, _ int) uint64 { return input.SubjectProfessors[entryKey[0]].Professor }))
+	assert.Equal(t, uint64(2), fixedLoad(input, 0, 0)) // Only Logica is taught by Luciano regardless of the choice
+	assert.Equal(t, uint64(0), fixedLoad(input, 1, 0))
+}
+
+func TestInvalidCandidateProfessors(t *testing.T) {
+	scenarios := []func(rawInput *rawModelInput){
+		func(rawInput *rawModelInput) { rawInput.Entries[1].Candidates = []uint64{1, 1} }, // Repeated candidate
+		func(rawInput *rawModelInput) { rawInput.Entries[1].Candidates = []uint64{1, 2} }, // Non-existing candidate
+		func(rawInput *rawModelInput) { rawInput.Entries[1].Professors = []uint64{0, 1} }, // Team and candidates
+		func(rawInput *rawModelInput) { rawInput.Professors[0].MaxLoad = 1 },              // Fixed load exceeds the maximum
+	}
+
+	for _, modify := range scenarios {
+		// Arrange
+		rawInput := candidatesRawInput()
+		modify(&rawInput)
+
+		// Act
+		_, err := processRawInput(rawInput)
+
+		// Assert
+		assert.NotNil(t, err)
+	}
+}
+
+// Returns a raw input where Luciano teaches Logica to CC-111 in Aula 1, whereas either Luciano or Dalianys teaches Algebra to CC-112 in Aula 2,
+// twice each on two days with two periods each
+func candidatesRawInput() rawModelInput {
+	availability := func() [][]bool { return [][]bool{{true, true}, {true, true}} }
+	return rawModelInput{
+		Subjects: []Subject{{Id: 0, Name: "Logica"}, {Id: 1, Name: "Algebra"}},
+		Professors: []Professor{
+			{Id: 0, Name: "Luciano", Availability: availability()},
+			{Id: 1, Name: "Dalianys", Availability: availability()},
+		},
+		Classes: []rawClass{{Id: 0, Name: "CC-111", Size: 30}, {Id: 1, Name: "CC-112", Size: 30}},
+		Rooms:   []Room{{Id: 0, Name: "Aula 1", Capacity: 50}, {Id: 1, Name: "Aula 2", Capacity: 50}},
+		Entries: []rawEntry{
+			{Subject: 0, Professor: 0, Classes: []uint64{0}, Lessons: 2, Permissibility: availability(), Rooms: []uint64{0}},
+			{Subject: 1, Candidates: []uint64{0, 1}, Classes: []uint64{1}, Lessons: 2, Permissibility: availability(), Rooms: []uint64{1}},
+		},
+	}
+}
+
+func TestWeeks(t *testing.T) {
+	// Arrange
+	rawInput := weeksRawInput()
+	rawInput.Professors[0].Availability = [][]bool{{true, false}, {true, true}}
+	rawInput.Pinned = []rawAssignment{{Subject: 1, Professor: 1, Classes: []uint64{1}, Week: 1, Day: 1, Period: 0}}
+
+	// Act
+	input, err := processRawInput(rawInput)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, [][]bool{{true, false, true, false}, {true, true, true, true}}, input.Professors[0].Availability)
+	assert.Equal(t, uint64(1), input.Entries[[2]uint64{0, 0}].Lessons)
+	assert.Equal(t, uint64(2), input.Entries[[2]uint64{1, 1}].Lessons) // One lesson per week
+	assert.Equal(t, uint64(3), input.Pinned[0].Day)
+	week, day := WeekDay(input, 3)
+	assert.Equal(t, [2]uint64{1, 1}, [2]uint64{week, day})
+}
+
+func TestInvalidWeeks(t *testing.T) {
+	scenarios := []func(rawInput *rawModelInput){
+		func(rawInput *rawModelInput) { rawInput.Entries[0].WeeklyLessons = []uint64{1, 1, 1} }, // More counts than weeks
+		func(rawInput *rawModelInput) { // Pinned to a non-existing week
+			rawInput.Pinned = []rawAssignment{{Subject: 0, Professor: 0, Classes: []uint64{0}, Week: 2}}
+		},
+		func(rawInput *rawModelInput) { // Pinned to a day beyond the week
+			rawInput.Pinned = []rawAssignment{{Subject: 0, Professor: 0, Classes: []uint64{0}, Day: 2}}
+		},
+		func(rawInput *rawModelInput) { // Both A-week lessons fall on the single day of the week CC-111 is available at, although it's available on two days overall
+			rawInput.Classes[0].Availability = [][]bool{{true, false}, {true, false}}
+			rawInput.Entries[0].WeeklyLessons = []uint64{2, 0}
+		},
+	}
+
+	for _, modify := range scenarios {
+		// Arrange
+		rawInput := weeksRawInput()
+		modify(&rawInput)
+
+		// Act
+		_, err := processRawInput(rawInput)
+
+		// Assert
+		assert.NotNil(t, err)
+	}
+}
+
+// Returns a raw input spanning two weeks of two days with two periods each, where Luciano teaches Logica to CC-111 in Aula 1 on A weeks only
+// and Dalianys teaches Algebra to CC-112 in Aula 2 once a week
+func weeksRawInput() rawModelInput {
+	availability := func() [][]bool { return [][]bool{{true, true}, {true, true}} }
+	return rawModelInput{
+		Weeks:    2,
+		Subjects: []Subject{{Id: 0, Name: "Logica"}, {Id: 1, Name: "Algebra"}},
+		Professors: []Professor{
+			{Id: 0, Name: "Luciano", Availability: availability()},
+			{Id: 1, Name: "Dalianys", Availability: availability()},
+		},
+		Classes: []rawClass{{Id: 0, Name: "CC-111", Size: 30}, {Id: 1, Name: "CC-112", Size: 30}},
+		Rooms:   []Room{{Id: 0, Name: "Aula 1", Capacity: 50}, {Id: 1, Name: "Aula 2", Capacity: 50}},
+		Entries: []rawEntry{
+			{Subject: 0, Professor: 0, Classes: []uint64{0}, WeeklyLessons: []uint64{1, 0}, Permissibility: availability(), Rooms: []uint64{0}},
+			{Subject: 1, Professor: 1, Classes: []uint64{1}, WeeklyLessons: []uint64{1, 1}, Permissibility: availability(), Rooms: []uint64{1}},
+		},
+	}
+}
+
+func TestAssignments(t *testing.T) {
+	scenarios := []struct {
+		pinned, forbidden []rawAssignment
+		expected          []Assignment // Pinned followed by forbidden assignments, nil if they're invalid
+	}{
+		{
+			pinned:   []rawAssignment{{Classes: []uint64{0}, Lesson: lo.ToPtr(uint64(1)), Day: 1, Period: 0, Room: lo.ToPtr(uint64(0))}},
+			expected: []Assignment{{SubjectProfessor: 0, Group: 0, Lesson: 1, Day: 1, Period: 0, Room: 0}},
+		},
+		{
+			forbidden: []rawAssignment{{Classes: []uint64{0}, Day: 0, Period: 1}, {Classes: []uint64{0}, Room: lo.ToPtr(uint64(2))}}, // Lab 1 is harmless
+			expected:  []Assignment{{SubjectProfessor: 0, Group: 0, Lesson: Any, Day: 0, Period: 1, Room: Any}, {SubjectProfessor: 0, Group: 0, Lesson: Any, Room: 2}},
+		},
+		{pinned: []rawAssignment{{Subject: 1, Classes: []uint64{0}}}},                                   // Non-existing entry
+		{pinned: []rawAssignment{{Classes: []uint64{0}, Period: 2}}},                                    // Out of range
+		{pinned: []rawAssignment{{Classes: []uint64{0}, Lesson: lo.ToPtr(uint64(2))}}},                  // Non-existing lesson
+		{forbidden: []rawAssignment{{Classes: []uint64{0}, Room: lo.ToPtr(uint64(4))}}},                 // Non-existing room
+		{pinned: []rawAssignment{{Classes: []uint64{0}, Day: 1, Period: 1}}},                            // Not permitted
+		{pinned: []rawAssignment{{Classes: []uint64{0}, Room: lo.ToPtr(uint64(2))}}},                    // Lab 1 is not among the entry's rooms
+		{pinned: []rawAssignment{{Classes: []uint64{0}, Room: lo.ToPtr(uint64(3))}}},                    // Lab 2 is too small
+		{pinned: []rawAssignment{{Classes: []uint64{0}, Day: 1, Period: 0, Room: lo.ToPtr(uint64(1))}}}, // Aula 2 is booked
+	}
+
+	for _, scenario := range scenarios {
+		// Arrange
+		rawInput := rawModelInput{ // Luciano teaches Logica to CC-111 twice on two days with two periods each, but not at period 1 on day 1
+			Subjects:   []Subject{{Id: 0, Name: "Logica"}},
+			Professors: []Professor{{Id: 0, Name: "Luciano", Availability: [][]bool{{true, true}, {true, true}}}},
+			Classes:    []rawClass{{Id: 0, Name: "CC-111", Size: 30}},
+			Rooms: []Room{
+				{Id: 0, Name: "Aula 1", Capacity: 50},
+				{Id: 1, Name: "Aula 2", Capacity: 50, Availability: [][]bool{{true, false}, {true, true}}},
+				{Id: 2, Name: "Lab 1", Capacity: 50},
+				{Id: 3, Name: "Lab 2", Capacity: 10},
+			},
+			Entries: []rawEntry{
+				{Subject: 0, Professor: 0, Classes: []uint64{0}, Lessons: 2, Permissibility: [][]bool{{true, true}, {true, false}}, Rooms: []uint64{0, 1, 3}},
+			},
+			Pinned:    scenario.pinned,
+			Forbidden: scenario.forbidden,
+		}
+
+		// Act
+		input, err := processRawInput(rawInput)
+
+		// Assert
+		if scenario.expected == nil {
+			assert.NotNil(t, err, "pinned = %v, forbidden = %v", scenario.pinned, scenario.forbidden)
+			continue
+		}
+		assert.Nil(t, err)
+		assert.Equal(t, scenario.expected, append(input.Pinned, input.Forbidden...))
+	}
+}
+
+func TestSimultaneous(t *testing.T) {
+	reference := func(subject, professor, class uint64) rawEntryReference {
+		return rawEntryReference{Subject: subject, Professor: professor, Classes: []uint64{class}}
+	}
+	scenarios := []struct {
+		references []rawEntryReference
+		expected   [][2]uint64 // Nil if the group is invalid
+	}{
+		{[]rawEntryReference{reference(0, 0, 0), reference(1, 1, 1)}, [][2]uint64{{0, 0}, {1, 1}}},
+		{[]rawEntryReference{reference(0, 0, 0), reference(0, 0, 0)}, nil}, // Repeated entry
+		{[]rawEntryReference{reference(0, 0, 0), reference(1, 1, 0)}, nil}, // Non-existing entry
+		{[]rawEntryReference{reference(0, 0, 0), reference(2, 2, 2)}, nil}, // Different number of lessons
+		{[]rawEntryReference{reference(0, 0, 0), reference(3, 0, 3)}, nil}, // Luciano teaches both entries
+		{[]rawEntryReference{reference(1, 1, 1), reference(4, 3, 1)}, nil}, // CC-112 attends both entries
+	}
+
+	for _, scenario := range scenarios {
+		// Arrange
+		availability := func() [][]bool { return [][]bool{{true, true}, {true, true}} }
+		rawInput := rawModelInput{
+			Subjects: []Subject{{Id: 0, Name: "Logica"}, {Id: 1, Name: "Algebra"}, {Id: 2, Name: "Programacion"}, {Id: 3, Name: "Analisis"}, {Id: 4, Name: "Fisica"}},
+			Professors: []Professor{
+				{Id: 0, Name: "Luciano", Availability: availability()},
+				{Id: 1, Name: "Dalianys", Availability: availability()},
+				{Id: 2, Name: "Celia", Availability: availability()},
+				{Id: 3, Name: "Fernando", Availability: availability()},
+			},
+			Classes: []rawClass{{Id: 0, Name: "CC-111", Size: 30}, {Id: 1, Name: "CC-112", Size: 30}, {Id: 2, Name: "CC-113", Size: 30}, {Id: 3, Name: "CC-114", Size: 30}},
+			Rooms:   []Room{{Id: 0, Name: "Aula 1", Capacity: 50}},
+			Entries: []rawEntry{
+				{Subject: 0, Professor: 0, Classes: []uint64{0}, Lessons: 2, Permissibility: availability(), Rooms: []uint64{0}},
+				{Subject: 1, Professor: 1, Classes: []uint64{1}, Lessons: 2, Permissibility: availability(), Rooms: []uint64{0}},
+				{Subject: 2, Professor: 2, Classes: []uint64{2}, Lessons: 1, Permissibility: availability(), Rooms: []uint64{0}},
+				{Subject: 3, Professor: 0, Classes: []uint64{3}, Lessons: 2, Permissibility: availability(), Rooms: []uint64{0}},
+				{Subject: 4, Professor: 3, Classes: []uint64{1}, Lessons: 2, Permissibility: availability(), Rooms: []uint64{0}},
+			},
+			Simultaneous: [][]rawEntryReference{scenario.references},
+		}
+
+		// Act
+		input, err := processRawInput(rawInput)
+
+		// Assert
+		if scenario.expected == nil {
+			assert.NotNil(t, err, "references = %v", scenario.references)
+			continue
+		}
+		assert.Nil(t, err)
+		assert.Equal(t, [][][2]uint64{scenario.expected}, input.Simultaneous)
+	}
+}
+
+func TestTravelTimes(t *testing.T) {
+	scenarios := []struct {
+		travelTimes [][]uint64
+		breaks      []uint64
+		building    uint64 // Building of Lab 1
+		valid       bool
+	}{
+		{[][]uint64{{0, 10}, {10, 0}}, []uint64{5}, 1, true},
+		{[][]uint64{{0, 10}, {10, 0}}, nil, 1, true},
+		{nil, nil, 2, true}, // Buildings are not checked without travel times
+		{[][]uint64{{0, 10}}, []uint64{5}, 1, false},
+		{[][]uint64{{0, 10}, {10}}, []uint64{5}, 1, false},
+		{[][]uint64{{0, 10}, {10, 0}}, []uint64{5}, 2, false}, // Non-existing building
+		{[][]uint64{{0, 10}, {10, 0}}, []uint64{5, 5}, 1, false},
+	}
+
+	for _, scenario := range scenarios {
+		// Arrange
+		rawInput := rawModelInput{ // Luciano teaches Logica to CC-111 once on a single day with two periods
+			Subjects:    []Subject{{Id: 0, Name: "Logica"}},
+			Professors:  []Professor{{Id: 0, Name: "Luciano", Availability: [][]bool{{true}, {true}}}},
+			Classes:     []rawClass{{Id: 0, Name: "CC-111", Size: 30}},
+			Buildings:   []Building{{Id: 0, Name: "Edificio Central"}, {Id: 1, Name: "Edificio de Laboratorios"}},
+			Rooms:       []Room{{Id: 0, Name: "Aula 1", Capacity: 50, Building: 0}, {Id: 1, Name: "Lab 1", Capacity: 50, Building: scenario.building}},
+			Entries:     []rawEntry{{Subject: 0, Professor: 0, Classes: []uint64{0}, Lessons: 1, Permissibility: [][]bool{{true}, {true}}, Rooms: []uint64{0, 1}}},
+			TravelTimes: scenario.travelTimes,
+			Breaks:      scenario.breaks,
+		}
+
+		// Act
+		_, err := processRawInput(rawInput)
+
+		// Assert
+		assert.Equal(t, scenario.valid, err == nil, "travelTimes = %v, breaks = %v, building = %d", scenario.travelTimes, scenario.breaks, scenario.building)
+	}
+}
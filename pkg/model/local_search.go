@@ -0,0 +1,107 @@
+package model
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"time"
+)
+
+const (
+	coolingRate        = 0.9995 // Factor applied to the temperature after each iteration
+	minimumTemperature = 1e-3   // Temperature (relative to the initial one) at which the search stops
+)
+
+type localSearchTimetabler struct {
+	timetabler Timetabler
+	seed       int64
+	budget     time.Duration
+}
+
+// Returns an optimizer that improves the timetables built by the given timetabler by means of Improve
+func NewLocalSearchTimetabler(timetabler Timetabler, seed int64, budget time.Duration) Optimizer {
+	return &localSearchTimetabler{
+		timetabler: timetabler,
+		seed:       seed,
+		budget:     budget,
+	}
+}
+
+func (timetabler *localSearchTimetabler) Build(modelInput ModelInput) (timetable [][6]uint64, variables uint64, clauses uint64, err error) {
+	timetable, variables, clauses, err = timetabler.timetabler.Build(modelInput)
+	if err != nil || timetable == nil {
+		return timetable, variables, clauses, err
+	}
+	timetable, _ = Improve(timetable, modelInput, timetabler.seed, timetabler.budget)
+	return timetable, variables, clauses, nil
+}
+
+func (timetabler *localSearchTimetabler) Optimize(modelInput ModelInput) (timetable [][6]uint64, cost Cost, err error) {
+	timetable, _, _, err = timetabler.timetabler.Build(modelInput)
+	if err != nil || timetable == nil {
+		return nil, Cost{}, err
+	}
+	timetable, cost = Improve(timetable, modelInput, timetabler.seed, timetabler.budget)
+	return timetable, cost, nil
+}
+
+func (timetabler *localSearchTimetabler) Verify(timetable [][6]uint64, modelInput ModelInput) bool {
+	return timetabler.timetabler.Verify(timetable, modelInput)
+}
+
+// Improves a feasible timetable by means of simulated annealing, moving lessons to other slots or rooms and swapping the slots of two lessons,
+// while keeping every check of verify satisfied. The minimized objective is the cost given by the input's weights. The search is deterministic
+// for a given seed, unless the budget (0 means no limit) expires before the temperature cools down
+func Improve(timetable [][6]uint64, modelInput ModelInput, seed int64, budget time.Duration) ([][6]uint64, Cost) {
+	current := slices.Clone(timetable)
+	currentCost := Evaluate(current, modelInput)
+	best, bestCost := current, currentCost
+
+	weights := modelInput.Weights
+	heaviest := max(weights.PreferredSlots, weights.LastPeriod, weights.Gaps, weights.CompactDays, weights.Preferences, weights.DislikedSlots, weights.RoomChanges, weights.RoomStability)
+	if heaviest == 0 || len(current) == 0 || !verify(current, modelInput) {
+		return best, bestCost
+	}
+
+	totalPeriods, totalDays, _, _, _, _ := getAttributes(modelInput)
+	random := rand.New(rand.NewSource(seed))
+	start := time.Now()
+
+	// Worsening the cost by the heaviest weight is initially accepted with a probability of about 60%
+	initialTemperature := 2 * float64(heaviest)
+	for temperature := initialTemperature; temperature > minimumTemperature*initialTemperature && bestCost.Total() > 0; temperature *= coolingRate {
+		if budget > 0 && time.Since(start) > budget {
+			break
+		}
+
+		candidate := slices.Clone(current)
+		i := random.Intn(len(candidate))
+		rooms := modelInput.Entries[[2]uint64{candidate[i][3], candidate[i][4]}].Rooms
+		switch move := random.Float64(); {
+		case move < 0.5: // Move a lesson to another slot and room
+			candidate[i][0], candidate[i][1] = uint64(random.Intn(int(totalPeriods))), uint64(random.Intn(int(totalDays)))
+			candidate[i][5] = rooms[random.Intn(len(rooms))]
+		case move < 0.8: // Swap the slots of two lessons
+			j := random.Intn(len(candidate))
+			candidate[i][0], candidate[i][1], candidate[j][0], candidate[j][1] = candidate[j][0], candidate[j][1], candidate[i][0], candidate[i][1]
+		default: // Move a lesson to another room
+			candidate[i][5] = rooms[random.Intn(len(rooms))]
+		}
+
+		if !verify(candidate, modelInput) {
+			continue
+		}
+
+		// Accept improvements and, with a probability decreasing with the temperature, deteriorations
+		candidateCost := Evaluate(candidate, modelInput)
+		delta := float64(candidateCost.Total()) - float64(currentCost.Total())
+		if delta <= 0 || random.Float64() < math.Exp(-delta/temperature) {
+			current, currentCost = candidate, candidateCost
+			if currentCost.Total() < bestCost.Total() {
+				best, bestCost = current, currentCost
+			}
+		}
+	}
+
+	return best, bestCost
+}
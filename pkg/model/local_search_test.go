@@ -0,0 +1,36 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImprove(t *testing.T) {
+	// Arrange
+	input, err := processRawInput(rawModelInput{ // Luciano teaches Logica to CC-111 twice, on two days with two periods each
+		Subjects:   []Subject{{Id: 0, Name: "Logica"}},
+		Professors: []Professor{{Id: 0, Name: "Luciano", Availability: [][]bool{{true, true}, {true, true}}}},
+		Classes:    []rawClass{{Id: 0, Name: "CC-111", Size: 30}},
+		Rooms:      []Room{{Id: 0, Name: "Aula 1", Capacity: 50}},
+		Entries: []rawEntry{
+			{Subject: 0, Professor: 0, Classes: []uint64{0}, Lessons: 2, Permissibility: [][]bool{{true, true}, {true, true}}, Rooms: []uint64{0}},
+		},
+	})
+	assert.Nil(t, err)
+	input.Weights = Weights{LastPeriod: 1}
+	timetable := [][6]uint64{{1, 0, 0, 0, 0, 0}, {1, 1, 1, 0, 0, 0}} // Every lesson is taught at the last period
+	assert.True(t, verify(timetable, input))
+
+	for _, seed := range []int64{1, 2, 3} {
+		// Act
+		improved, cost := Improve(timetable, input, seed, 0)
+		again, _ := Improve(timetable, input, seed, 0)
+
+		// Assert
+		assert.True(t, verify(improved, input))
+		assert.Equal(t, uint64(0), cost.Total())
+		assert.Equal(t, Evaluate(improved, input), cost)
+		assert.Equal(t, improved, again) // The search is deterministic for a given seed
+	}
+}
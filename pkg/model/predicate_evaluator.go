@@ -7,6 +7,9 @@ type predicateEvaluator interface {
 	// Checks whether the subjectProfessor is available to teach at the given day and period
 	ProfessorAvailable(subjectProfessor, day, period uint64) bool
 
+	// Checks whether the room is available (i.e. not booked elsewhere) at the given day and period
+	RoomAvailable(room, day, period uint64) bool
+
 	// Checks whether the subjectProfessor teaches the lesson to the group
 	Teaches(group, subjectProfessor, lesson uint64) bool
 
@@ -24,4 +27,13 @@ type predicateEvaluator interface {
 
 	// Checks whether two subject-professor are similar according to their assigned-rooms
 	RoomSimilar(subjectProfessor1, subjectProfessor2, group1, group2 uint64) bool
+
+	// Checks whether teaching the lesson in the room satisfies the pinned assignment (pinned is an index of the pinned assignments)
+	Pinned(pinned, lesson, room uint64) bool
+
+	// Checks whether teaching the lesson in the room matches the forbidden assignment (forbidden is an index of the forbidden assignments)
+	Forbidden(forbidden, lesson, room uint64) bool
+
+	// Checks whether room2 can be reached from room1 during the break following the period
+	Reachable(room1, room2, period uint64) bool
 }
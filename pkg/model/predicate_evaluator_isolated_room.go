@@ -1,12 +1,14 @@
 package model
 
 type predicateEvaluatorIsolatedRoom struct {
-	e predicateEvaluator
+	e          predicateEvaluator
+	modelInput ModelInput
 }
 
 func newPredicateEvaluatorIsolatedRoom(modelInput ModelInput, roomSimilarityThreshold float32) predicateEvaluator {
 	evaluator := predicateEvaluatorIsolatedRoom{
-		e: newPredicateEvaluator(modelInput, roomSimilarityThreshold),
+		e:          newPredicateEvaluator(modelInput, roomSimilarityThreshold),
+		modelInput: modelInput,
 	}
 	return &evaluator
 }
@@ -19,6 +21,11 @@ func (evaluator *predicateEvaluatorIsolatedRoom) ProfessorAvailable(subjectProfe
 	return evaluator.e.ProfessorAvailable(subjectProfessor, day, period)
 }
 
+// Rooms are assigned after solving, therefore their availability is not taken into account
+func (evaluator *predicateEvaluatorIsolatedRoom) RoomAvailable(room, day, period uint64) bool {
+	return true
+}
+
 func (evaluator *predicateEvaluatorIsolatedRoom) Teaches(group, subjectProfessor, lesson uint64) bool {
 	return evaluator.e.Teaches(group, subjectProfessor, lesson)
 }
@@ -42,3 +49,19 @@ func (evaluator *predicateEvaluatorIsolatedRoom) Fits(group, room uint64) bool {
 func (evaluator *predicateEvaluatorIsolatedRoom) RoomSimilar(subjectProfessor1, subjectProfessor2, group1, group2 uint64) bool {
 	return evaluator.e.RoomSimilar(subjectProfessor1, subjectProfessor2, group1, group2)
 }
+
+// Rooms are assigned after solving, therefore only the lesson is taken into account
+func (evaluator *predicateEvaluatorIsolatedRoom) Pinned(pinned, lesson, room uint64) bool {
+	pin := evaluator.modelInput.Pinned[pinned]
+	return pin.Lesson == Any || pin.Lesson == lesson
+}
+
+// Rooms are assigned after solving, therefore only forbidden assignments applying to any room are taken into account
+func (evaluator *predicateEvaluatorIsolatedRoom) Forbidden(forbidden, lesson, room uint64) bool {
+	assignment := evaluator.modelInput.Forbidden[forbidden]
+	return assignment.Room == Any && (assignment.Lesson == Any || assignment.Lesson == lesson)
+}
+
+func (evaluator *predicateEvaluatorIsolatedRoom) Reachable(room1, room2, period uint64) bool {
+	return true
+}
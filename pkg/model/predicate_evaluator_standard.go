@@ -47,16 +47,23 @@ func newPredicateEvaluator(modelInput ModelInput, roomSimilarityThreshold float3
 	return &evaluator
 }
 
+// Two subject-professors share a professor if their teams intersect
 func (evaluator *predicateEvaluatorStandard) SameProfessor(subjectProfessor1, subjectProfessor2 uint64) bool {
-	professor1 := evaluator.modelInput.SubjectProfessors[subjectProfessor1].Professor
-	professor2 := evaluator.modelInput.SubjectProfessors[subjectProfessor2].Professor
-	return professor1 == professor2
+	team1 := evaluator.modelInput.SubjectProfessors[subjectProfessor1].Team()
+	team2 := evaluator.modelInput.SubjectProfessors[subjectProfessor2].Team()
+	return lo.Some(team1, team2)
 }
 
+// A subject-professor is available if every professor of its team is available
 func (evaluator *predicateEvaluatorStandard) ProfessorAvailable(subjectProfessor, day, period uint64) bool {
-	professorId := evaluator.modelInput.SubjectProfessors[subjectProfessor].Professor
-	distribution := evaluator.modelInput.Professors[professorId].Availability
-	return distribution[period][day]
+	return lo.EveryBy(evaluator.modelInput.SubjectProfessors[subjectProfessor].Team(), func(professor uint64) bool {
+		return evaluator.modelInput.Professors[professor].Availability[period][day]
+	})
+}
+
+func (evaluator *predicateEvaluatorStandard) RoomAvailable(room, day, period uint64) bool {
+	availability := evaluator.modelInput.Rooms[room].Availability
+	return len(availability) == 0 || availability[period][day]
 }
 
 func (evaluator *predicateEvaluatorStandard) Teaches(group, subjectProfessor, lesson uint64) bool {
@@ -137,10 +144,31 @@ func (evaluator *predicateEvaluatorStandard) RoomSimilar(subjectProfessor1, subj
 	return jaccardSimilarity >= evaluator.roomSimilarityThreshold
 }
 
+func (evaluator *predicateEvaluatorStandard) Pinned(pinned, lesson, room uint64) bool {
+	return matches(evaluator.modelInput.Pinned[pinned], lesson, room)
+}
+
+func (evaluator *predicateEvaluatorStandard) Forbidden(forbidden, lesson, room uint64) bool {
+	return matches(evaluator.modelInput.Forbidden[forbidden], lesson, room)
+}
+
+func (evaluator *predicateEvaluatorStandard) Reachable(room1, room2, period uint64) bool {
+	if len(evaluator.modelInput.TravelTimes) == 0 {
+		return true
+	}
+
+	building1, building2 := evaluator.modelInput.Rooms[room1].Building, evaluator.modelInput.Rooms[room2].Building
+	breakTime := uint64(0)
+	if period < uint64(len(evaluator.modelInput.Breaks)) {
+		breakTime = evaluator.modelInput.Breaks[period]
+	}
+	return evaluator.modelInput.TravelTimes[building1][building2] <= breakTime
+}
+
 func (evaluator *predicateEvaluatorStandard) noRoomsErrorMessage(subjectProfessor, group uint64) string {
 	var builder strings.Builder
 	subjectName := evaluator.modelInput.Subjects[evaluator.modelInput.SubjectProfessors[subjectProfessor].Subject].Name
-	professorName := evaluator.modelInput.Professors[evaluator.modelInput.SubjectProfessors[subjectProfessor].Professor].Name
+	professorName := teamName(evaluator.modelInput, evaluator.modelInput.SubjectProfessors[subjectProfessor])
 
 	fmt.Fprintf(&builder, "There are not fitting rooms for: %v~%v to { ", subjectName, professorName)
 	for _, class := range evaluator.modelInput.Groups[group].Classes {
@@ -149,3 +177,8 @@ func (evaluator *predicateEvaluatorStandard) noRoomsErrorMessage(subjectProfesso
 	builder.WriteString("}")
 	return builder.String()
 }
+
+// Checks whether the lesson and room match the assignment's ones, taking into account that both can be set to Any
+func matches(assignment Assignment, lesson, room uint64) bool {
+	return (assignment.Lesson == Any || assignment.Lesson == lesson) && (assignment.Room == Any || assignment.Room == room)
+}
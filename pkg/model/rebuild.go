@@ -0,0 +1,57 @@
+package model
+
+import "github.com/limaJavier/timetabling/pkg/sat"
+
+// Returns the positives of the previous timetable that can still be kept under the (possibly edited) input, i.e. those whose entry
+// can be taught at the same period, day and room
+func keepableAssignments(previous [][6]uint64, modelInput ModelInput) [][6]uint64 {
+	feasible := make(map[[5]uint64]bool)
+	for _, permutation := range feasiblePermutations(newEmbeddedRoomState(modelInput)) {
+		feasible[slotKey(permutation[0], permutation[1], permutation[3], permutation[4], permutation[5])] = true
+	}
+
+	keepable := make([][6]uint64, 0, len(previous))
+	for _, positive := range previous {
+		if feasible[slotKey(positive[0], positive[1], positive[3], positive[4], positive[5])] {
+			keepable = append(keepable, positive)
+		}
+	}
+	return keepable
+}
+
+// Returns the assignments that are not kept by the timetable, regardless of their lesson
+func lostAssignments(assignments, timetable [][6]uint64) [][6]uint64 {
+	kept := make(map[[5]uint64]bool)
+	for _, positive := range timetable {
+		kept[slotKey(positive[0], positive[1], positive[3], positive[4], positive[5])] = true
+	}
+
+	lost := make([][6]uint64, 0)
+	for _, positive := range assignments {
+		if !kept[slotKey(positive[0], positive[1], positive[3], positive[4], positive[5])] {
+			lost = append(lost, positive)
+		}
+	}
+	return lost
+}
+
+// Returns the soft constraints stating that some lesson of each assignment's entry should be taught at the assignment's period, day and room
+func previousAssignmentsSoftConstraints(assignments [][6]uint64, weight uint64) softConstraint {
+	return func(state constraintState) ([][]int64, []sat.WeightedClause) {
+		soft := make([]sat.WeightedClause, 0, len(assignments))
+		for _, positive := range assignments {
+			period, day, subjectProfessor, group, room := positive[0], positive[1], positive[3], positive[4], positive[5]
+
+			literals := make([]int64, 0)
+			for lesson := range state.modelInput.Entries[[2]uint64{subjectProfessor, group}].Lessons {
+				literals = append(literals, int64(state.indexer.Index(period, day, lesson, subjectProfessor, group, room)))
+			}
+			soft = append(soft, sat.WeightedClause{Weight: weight, Literals: literals})
+		}
+		return nil, soft
+	}
+}
+
+func slotKey(period, day, subjectProfessor, group, room uint64) [5]uint64 {
+	return [5]uint64{period, day, subjectProfessor, group, room}
+}
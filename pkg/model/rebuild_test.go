@@ -0,0 +1,88 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeepableAssignments(t *testing.T) {
+	scenarios := []struct {
+		permissibility [][]bool
+		rooms          []uint64
+		keepable       [][6]uint64
+	}{
+		{[][]bool{{true, true}, {true, true}}, []uint64{0, 1}, [][6]uint64{{0, 0, 0, 0, 0, 0}, {1, 1, 1, 0, 0, 1}}},
+		{[][]bool{{false, true}, {true, true}}, []uint64{0, 1}, [][6]uint64{{1, 1, 1, 0, 0, 1}}}, // The first slot is no longer permitted
+		{[][]bool{{true, true}, {true, true}}, []uint64{0}, [][6]uint64{{0, 0, 0, 0, 0, 0}}},     // Aula 2 is no longer assigned
+	}
+
+	for _, scenario := range scenarios {
+		// Arrange
+		previous := [][6]uint64{{0, 0, 0, 0, 0, 0}, {1, 1, 1, 0, 0, 1}}
+		input, err := processRawInput(rawModelInput{ // Luciano teaches Logica to CC-111 twice on two days with two periods each
+			Subjects:   []Subject{{Id: 0, Name: "Logica"}},
+			Professors: []Professor{{Id: 0, Name: "Luciano", Availability: [][]bool{{true, true}, {true, true}}}},
+			Classes:    []rawClass{{Id: 0, Name: "CC-111", Size: 30}},
+			Rooms:      []Room{{Id: 0, Name: "Aula 1", Capacity: 50}, {Id: 1, Name: "Aula 2", Capacity: 50}},
+			Entries:    []rawEntry{{Subject: 0, Professor: 0, Classes: []uint64{0}, Lessons: 2, Permissibility: scenario.permissibility, Rooms: scenario.rooms}},
+		})
+		assert.Nil(t, err)
+
+		// Act
+		keepable := keepableAssignments(previous, input)
+
+		// Assert
+		assert.Equal(t, scenario.keepable, keepable, "permissibility = %v, rooms = %v", scenario.permissibility, scenario.rooms)
+	}
+}
+
+func TestLostAssignments(t *testing.T) {
+	assignments := [][6]uint64{{0, 0, 0, 0, 0, 0}, {1, 1, 1, 0, 0, 0}}
+	scenarios := []struct {
+		timetable [][6]uint64
+		lost      [][6]uint64
+	}{
+		{[][6]uint64{{0, 0, 0, 0, 0, 0}, {1, 1, 1, 0, 0, 0}}, [][6]uint64{}},
+		{[][6]uint64{{0, 0, 1, 0, 0, 0}, {1, 1, 0, 0, 0, 0}}, [][6]uint64{}}, // Lessons are interchangeable
+		{[][6]uint64{{0, 0, 0, 0, 0, 0}, {1, 1, 1, 0, 0, 1}}, [][6]uint64{{1, 1, 1, 0, 0, 0}}},
+		{[][6]uint64{{0, 1, 0, 0, 0, 0}, {1, 0, 1, 0, 0, 0}}, [][6]uint64{{0, 0, 0, 0, 0, 0}, {1, 1, 1, 0, 0, 0}}},
+	}
+
+	for _, scenario := range scenarios {
+		// Act
+		lost := lostAssignments(assignments, scenario.timetable)
+
+		// Assert
+		assert.Equal(t, scenario.lost, lost, "timetable = %v", scenario.timetable)
+	}
+}
+
+func TestRebuild(t *testing.T) {
+	// Arrange
+	input, err := processRawInput(rawModelInput{ // Luciano teaches Logica to CC-111 and Algebra to CC-112 in Aula 1, once each on a single day with three periods
+		Subjects:   []Subject{{Id: 0, Name: "Logica"}, {Id: 1, Name: "Algebra"}},
+		Professors: []Professor{{Id: 0, Name: "Luciano", Availability: [][]bool{{true}, {true}, {true}}}},
+		Classes:    []rawClass{{Id: 0, Name: "CC-111", Size: 30}, {Id: 1, Name: "CC-112", Size: 30}},
+		Rooms:      []Room{{Id: 0, Name: "Aula 1", Capacity: 50}},
+		Entries: []rawEntry{
+			{Subject: 0, Professor: 0, Classes: []uint64{0}, Lessons: 1, Permissibility: [][]bool{{false}, {true}, {true}}, Rooms: []uint64{0}}, // No longer permitted at the first period
+			{Subject: 1, Professor: 0, Classes: []uint64{1}, Lessons: 1, Permissibility: [][]bool{{true}, {true}, {true}}, Rooms: []uint64{0}},
+		},
+	})
+	assert.Nil(t, err)
+	previous := [][6]uint64{{0, 0, 0, 0, 0, 0}, {1, 0, 0, 1, 1, 0}} // Luciano teaches Logica to CC-111 and then Algebra to CC-112
+	timetabler := NewIterativeTimetabler(dpllSolver{}, ObjectiveGaps, LinearSearch, time.Minute)
+
+	// Act
+	timetable, diff, err := timetabler.Rebuild(previous, input)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.True(t, verify(timetable, input))
+	assert.Equal(t, [][6]uint64{{0, 0, 0, 0, 0, 0}}, lostAssignments(previous, timetable))
+	assert.Equal(t, []Move{{From: [6]uint64{0, 0, 0, 0, 0, 0}, To: [6]uint64{2, 0, 0, 0, 0, 0}}}, diff.Moved)
+	assert.Empty(t, diff.Added)
+	assert.Empty(t, diff.Removed)
+}
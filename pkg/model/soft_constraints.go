@@ -0,0 +1,350 @@
+package model
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/limaJavier/timetabling/pkg/sat"
+	"github.com/samber/lo"
+)
+
+// Soft constraints return the hard clauses defining their auxiliary variables along with the (weighted) soft clauses to be satisfied
+type softConstraint func(state constraintState) (hard [][]int64, soft []sat.WeightedClause)
+
+func softConstraints() []softConstraint {
+	return []softConstraint{
+		preferredSlotsSoftConstraints,
+		lastPeriodSoftConstraints,
+		gapsSoftConstraints,
+		compactDaysSoftConstraints,
+		preferencesSoftConstraints,
+		dislikedSlotsSoftConstraints,
+		roomChangesSoftConstraints,
+		roomStabilitySoftConstraints,
+	}
+}
+
+func preferredSlotsSoftConstraints(state constraintState) ([][]int64, []sat.WeightedClause) {
+	weight := state.modelInput.Weights.PreferredSlots
+	soft := make([]sat.WeightedClause, 0)
+	if weight == 0 {
+		return nil, soft
+	}
+
+	for _, permutation := range feasiblePermutations(state) {
+		period, day, lesson, subjectProfessor, group, room := permutation[0], permutation[1], permutation[2], permutation[3], permutation[4], permutation[5]
+
+		// Lessons should not be taught outside the professor's preferred slots
+		if !preferred(state.modelInput, subjectProfessor, day, period) {
+			index := state.indexer.Index(period, day, lesson, subjectProfessor, group, room)
+			soft = append(soft, sat.WeightedClause{Weight: weight, Literals: []int64{-int64(index)}})
+		}
+	}
+
+	return nil, soft
+}
+
+func lastPeriodSoftConstraints(state constraintState) ([][]int64, []sat.WeightedClause) {
+	weight := state.modelInput.Weights.LastPeriod
+	soft := make([]sat.WeightedClause, 0)
+	if weight == 0 {
+		return nil, soft
+	}
+
+	for _, permutation := range feasiblePermutations(state) {
+		period, day, lesson, subjectProfessor, group, room := permutation[0], permutation[1], permutation[2], permutation[3], permutation[4], permutation[5]
+
+		// Lessons should not be taught at the last period of the day
+		if period == state.periods-1 {
+			index := state.indexer.Index(period, day, lesson, subjectProfessor, group, room)
+			soft = append(soft, sat.WeightedClause{Weight: weight, Literals: []int64{-int64(index)}})
+		}
+	}
+
+	return nil, soft
+}
+
+func gapsSoftConstraints(state constraintState) ([][]int64, []sat.WeightedClause) {
+	weight := state.modelInput.Weights.Gaps
+	hard, soft := make([][]int64, 0), make([]sat.WeightedClause, 0)
+	if weight == 0 || state.periods < 3 {
+		return hard, soft
+	}
+
+	// Scheduling variables attending each class at a given period and day
+	attending := make(map[[3]uint64][]int64)
+	for _, permutation := range feasiblePermutations(state) {
+		period, day, lesson, subjectProfessor, group, room := permutation[0], permutation[1], permutation[2], permutation[3], permutation[4], permutation[5]
+		index := state.indexer.Index(period, day, lesson, subjectProfessor, group, room)
+
+		for _, class := range state.modelInput.Groups[group].Classes {
+			key := [3]uint64{class, day, period}
+			attending[key] = append(attending[key], int64(index))
+		}
+	}
+
+	for class := range uint64(len(state.modelInput.Classes)) {
+		for day := range state.days {
+			// busy[t] is true if and only if the class attends a lesson at period t
+			busy := make([]int64, state.periods)
+			for period := range state.periods {
+				busy[period] = state.allocator.Next()
+				variables := attending[[3]uint64{class, day, period}]
+				for _, variable := range variables {
+					hard = append(hard, []int64{-variable, busy[period]})
+				}
+				hard = append(hard, append([]int64{-busy[period]}, variables...))
+			}
+
+			// before[t] (after[t]) is true if the class attends a lesson before (after) period t
+			before, after := make([]int64, state.periods), make([]int64, state.periods)
+			for period := uint64(1); period < state.periods; period++ {
+				before[period] = state.allocator.Next()
+				hard = append(hard, []int64{-busy[period-1], before[period]})
+				if period > 1 {
+					hard = append(hard, []int64{-before[period-1], before[period]})
+				}
+			}
+			for period := int64(state.periods) - 2; period >= 0; period-- {
+				after[period] = state.allocator.Next()
+				hard = append(hard, []int64{-busy[period+1], after[period]})
+				if period < int64(state.periods)-2 {
+					hard = append(hard, []int64{-after[period+1], after[period]})
+				}
+			}
+
+			// A gap at period t occurs when the class attends lessons before and after t but not at t
+			for period := uint64(1); period < state.periods-1; period++ {
+				gap := state.allocator.Next()
+				hard = append(hard, []int64{-before[period], busy[period], -after[period], gap})
+				soft = append(soft, sat.WeightedClause{Weight: weight, Literals: []int64{-gap}})
+			}
+		}
+	}
+
+	return hard, soft
+}
+
+func compactDaysSoftConstraints(state constraintState) ([][]int64, []sat.WeightedClause) {
+	weight := state.modelInput.Weights.CompactDays
+	hard, soft := make([][]int64, 0), make([]sat.WeightedClause, 0)
+	if weight == 0 {
+		return hard, soft
+	}
+
+	// Auxiliary variables stating whether a professor teaches on a given day
+	teaches := make(map[[2]uint64]int64)
+	for _, permutation := range feasiblePermutations(state) {
+		period, day, lesson, subjectProfessor, group, room := permutation[0], permutation[1], permutation[2], permutation[3], permutation[4], permutation[5]
+		index := state.indexer.Index(period, day, lesson, subjectProfessor, group, room)
+
+		for _, professor := range state.modelInput.SubjectProfessors[subjectProfessor].Team() {
+			key := [2]uint64{professor, day}
+			if _, ok := teaches[key]; !ok {
+				teaches[key] = state.allocator.Next()
+				// Professors should teach on as few days as possible
+				soft = append(soft, sat.WeightedClause{Weight: weight, Literals: []int64{-teaches[key]}})
+			}
+			hard = append(hard, []int64{-int64(index), teaches[key]})
+		}
+	}
+
+	return hard, soft
+}
+
+func preferencesSoftConstraints(state constraintState) ([][]int64, []sat.WeightedClause) {
+	weight := state.modelInput.Weights.Preferences
+	soft := make([]sat.WeightedClause, 0)
+	if weight == 0 {
+		return nil, soft
+	}
+
+	for _, permutation := range feasiblePermutations(state) {
+		period, day, lesson, subjectProfessor, group, room := permutation[0], permutation[1], permutation[2], permutation[3], permutation[4], permutation[5]
+
+		// Lessons should be taught at the slots their professor grades the highest
+		if penalty := preferencePenalty(state.modelInput, subjectProfessor, day, period); penalty > 0 {
+			index := state.indexer.Index(period, day, lesson, subjectProfessor, group, room)
+			soft = append(soft, sat.WeightedClause{Weight: penalty * weight, Literals: []int64{-int64(index)}})
+		}
+	}
+
+	return nil, soft
+}
+
+func dislikedSlotsSoftConstraints(state constraintState) ([][]int64, []sat.WeightedClause) {
+	weight := state.modelInput.Weights.DislikedSlots
+	soft := make([]sat.WeightedClause, 0)
+	if weight == 0 {
+		return nil, soft
+	}
+
+	for _, permutation := range feasiblePermutations(state) {
+		period, day, lesson, subjectProfessor, group, room := permutation[0], permutation[1], permutation[2], permutation[3], permutation[4], permutation[5]
+
+		// Lessons should not be taught at slots their professor dislikes
+		if disliked(state.modelInput, subjectProfessor, day, period) {
+			index := state.indexer.Index(period, day, lesson, subjectProfessor, group, room)
+			soft = append(soft, sat.WeightedClause{Weight: weight, Literals: []int64{-int64(index)}})
+		}
+	}
+
+	return nil, soft
+}
+
+func roomChangesSoftConstraints(state constraintState) ([][]int64, []sat.WeightedClause) {
+	weight := state.modelInput.Weights.RoomChanges
+	hard, soft := make([][]int64, 0), make([]sat.WeightedClause, 0)
+	if weight == 0 {
+		return hard, soft
+	}
+
+	// Scheduling variables attending each class at a given period, day and room
+	attending := make(map[[4]uint64][]int64)
+	for _, permutation := range feasiblePermutations(state) {
+		period, day, lesson, subjectProfessor, group, room := permutation[0], permutation[1], permutation[2], permutation[3], permutation[4], permutation[5]
+		index := state.indexer.Index(period, day, lesson, subjectProfessor, group, room)
+
+		for _, class := range state.modelInput.Groups[group].Classes {
+			key := [4]uint64{class, day, period, room}
+			attending[key] = append(attending[key], int64(index))
+		}
+	}
+
+	// in[c, d, t, r] is true if and only if class c attends a lesson in room r at period t and day d, and busy[c, d, t] is true if it attends any lesson
+	in, busy := make(map[[4]uint64]int64), make(map[[3]uint64]int64)
+	for key, variables := range attending {
+		in[key] = state.allocator.Next()
+		for _, variable := range variables {
+			hard = append(hard, []int64{-variable, in[key]})
+		}
+		hard = append(hard, append([]int64{-in[key]}, variables...))
+
+		busyKey := [3]uint64{key[0], key[1], key[2]}
+		if _, ok := busy[busyKey]; !ok {
+			busy[busyKey] = state.allocator.Next()
+		}
+		hard = append(hard, []int64{-in[key], busy[busyKey]})
+	}
+
+	// A room change at period t occurs when the class attends a lesson in room r at t and a lesson outside r at t+1
+	changes := make(map[[3]uint64]int64)
+	for key, variable := range in {
+		class, day, period, room := key[0], key[1], key[2], key[3]
+		nextBusy, ok := busy[[3]uint64{class, day, period + 1}]
+		if !ok {
+			continue
+		}
+
+		changeKey := [3]uint64{class, day, period}
+		if _, ok := changes[changeKey]; !ok {
+			changes[changeKey] = state.allocator.Next()
+			soft = append(soft, sat.WeightedClause{Weight: weight, Literals: []int64{-changes[changeKey]}})
+		}
+		clause := []int64{-variable, -nextBusy, changes[changeKey]}
+		if nextIn, ok := in[[4]uint64{class, day, period + 1, room}]; ok {
+			clause = append(clause, nextIn)
+		}
+		hard = append(hard, clause)
+	}
+
+	return hard, soft
+}
+
+func roomStabilitySoftConstraints(state constraintState) ([][]int64, []sat.WeightedClause) {
+	weight := state.modelInput.Weights.RoomStability
+	soft := make([]sat.WeightedClause, 0)
+	if weight == 0 {
+		return [][]int64{}, soft
+	}
+
+	stable, hard := roomStabilityVariables(state, true)
+	for _, variables := range stable {
+		// preceded[k] is true if some room before the k-th one is used within the scope, so that every used room but the first one is penalized
+		rooms := slices.Sorted(maps.Keys(variables))
+		preceded := int64(0)
+		for k := 1; k < len(rooms); k++ {
+			next := state.allocator.Next()
+			hard = append(hard, []int64{-variables[rooms[k-1]], next})
+			if preceded != 0 {
+				hard = append(hard, []int64{-preceded, next})
+			}
+			preceded = next
+			soft = append(soft, sat.WeightedClause{Weight: weight, Literals: []int64{-variables[rooms[k]], -preceded}})
+		}
+	}
+
+	return hard, soft
+}
+
+// Checks whether every professor of the subjectProfessor's team prefers to teach at the given period and day
+func preferred(modelInput ModelInput, subjectProfessor, day, period uint64) bool {
+	return lo.EveryBy(modelInput.SubjectProfessors[subjectProfessor].Team(), func(professor uint64) bool {
+		preferred := modelInput.Professors[professor].Preferred
+		return len(preferred) == 0 || preferred[period][day]
+	})
+}
+
+// Returns the professor's graded preference for the given period and day
+func professorPreference(modelInput ModelInput, professor, day, period uint64) uint64 {
+	preferences := modelInput.Professors[professor].Preferences
+	if len(preferences) == 0 {
+		return PreferenceNeutral
+	}
+	return preferences[period][day]
+}
+
+// Returns the lowest graded preference among the subjectProfessor's team for the given period and day
+func preference(modelInput ModelInput, subjectProfessor, day, period uint64) uint64 {
+	return lo.Min(lo.Map(modelInput.SubjectProfessors[subjectProfessor].Team(), func(professor uint64, _ int) uint64 {
+		return professorPreference(modelInput, professor, day, period)
+	}))
+}
+
+// Returns how many grades the slot falls below PreferencePreferred summed over the subjectProfessor's team, where professors without a preference
+// matrix are never penalized
+func preferencePenalty(modelInput ModelInput, subjectProfessor, day, period uint64) uint64 {
+	penalty := uint64(0)
+	for _, professor := range modelInput.SubjectProfessors[subjectProfessor].Team() {
+		if len(modelInput.Professors[professor].Preferences) > 0 {
+			penalty += PreferencePreferred - professorPreference(modelInput, professor, day, period)
+		}
+	}
+	return penalty
+}
+
+// Checks whether any professor of the subjectProfessor's team dislikes the given period and day, according to their preference matrices
+func disliked(modelInput ModelInput, subjectProfessor, day, period uint64) bool {
+	return preference(modelInput, subjectProfessor, day, period) <= PreferenceDisliked
+}
+
+// Returns the soft constraints evaluated under the given weights instead of the input's ones
+func weightedSoftConstraints(constraints []softConstraint, weights Weights) []softConstraint {
+	weighted := make([]softConstraint, len(constraints))
+	for i, constraint := range constraints {
+		weighted[i] = func(state constraintState) ([][]int64, []sat.WeightedClause) {
+			state.modelInput.Weights = weights
+			return constraint(state)
+		}
+	}
+	return weighted
+}
+
+// Extends the SAT instance with the given soft constraints into a MaxSAT instance
+func buildMaxSat(satInstance sat.SAT, constraints []softConstraint, state constraintState) sat.MaxSAT {
+	maxSatInstance := sat.MaxSAT{
+		Hard: slices.Clone(satInstance.Clauses),
+		Soft: []sat.WeightedClause{},
+	}
+
+	for _, constraint := range constraints {
+		hard, soft := constraint(state)
+		maxSatInstance.Hard = append(maxSatInstance.Hard, hard...)
+		maxSatInstance.Soft = append(maxSatInstance.Soft, soft...)
+	}
+
+	// Account for the auxiliary variables introduced by the soft constraints
+	maxSatInstance.Variables = state.allocator.Last()
+
+	return maxSatInstance
+}
@@ -0,0 +1,98 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/limaJavier/timetabling/pkg/sat"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSoftConstraints(t *testing.T) {
+	weights := Weights{PreferredSlots: 2, LastPeriod: 3, Gaps: 5, CompactDays: 7}
+	timetables := [][][6]uint64{
+		{{0, 0, 0, 0, 0, 0}, {0, 1, 1, 0, 0, 0}, {1, 0, 0, 1, 0, 0}}, // Optimal
+		{{0, 0, 0, 0, 0, 0}, {2, 1, 1, 0, 0, 0}, {2, 0, 0, 1, 0, 0}}, // Logica is taught at the last period outside Luciano's preferred slots, and CC-111 idles at period 1 on Monday
+	}
+	scenarios := []struct {
+		name       string
+		constraint softConstraint
+		weight     uint64
+		cost       func(cost Cost) uint64
+		costs      []uint64 // Cost of each timetable
+	}{
+		{"preferred-slots", preferredSlotsSoftConstraints, weights.PreferredSlots, func(cost Cost) uint64 { return cost.PreferredSlots }, []uint64{0, 2}},
+		{"last-period", lastPeriodSoftConstraints, weights.LastPeriod, func(cost Cost) uint64 { return cost.LastPeriod }, []uint64{0, 6}},
+		{"gaps", gapsSoftConstraints, weights.Gaps, func(cost Cost) uint64 { return cost.Gaps }, []uint64{0, 5}},
+		{"compact-days", compactDaysSoftConstraints, weights.CompactDays, func(cost Cost) uint64 { return cost.CompactDays }, []uint64{21, 21}}, // Luciano teaches on both days and Dalianys on one
+	}
+
+	for _, scenario := range scenarios {
+		for i, timetable := range timetables {
+			// Arrange
+			input := softInput(t, weights)
+			state := newEmbeddedRoomState(input)
+			fixed := make([][]int64, 0) // The timetable's scheduling variables, as unit clauses
+			for variable := range state.schedulingVariables() {
+				fixed = append(fixed, []int64{-int64(variable + 1)})
+			}
+			for _, positive := range timetable {
+				index := int64(state.indexer.Index(positive[0], positive[1], positive[2], positive[3], positive[4], positive[5]))
+				fixed[index-1] = []int64{index}
+			}
+
+			// Act
+			hard, soft := scenario.constraint(state)
+			_, cost, _ := branchAndBoundSolver{}.Solve(sat.MaxSAT{Variables: state.allocator.Last(), Hard: append(hard, fixed...), Soft: soft})
+
+			// Assert
+			assert.NotEmpty(t, soft, scenario.name)
+			for _, clause := range soft {
+				assert.Equal(t, scenario.weight, clause.Weight, scenario.name)
+			}
+			assert.True(t, verify(timetable, input), "%v: timetable %d", scenario.name, i)
+			assert.Equal(t, scenario.costs[i], cost, "%v: timetable %d", scenario.name, i)
+			assert.Equal(t, scenario.costs[i], scenario.cost(Evaluate(timetable, input)), "%v: timetable %d", scenario.name, i) // The encoding agrees with the cost's breakdown
+		}
+
+		// Nothing is penalized without weight
+		_, soft := scenario.constraint(newEmbeddedRoomState(softInput(t, Weights{})))
+		assert.Empty(t, soft, scenario.name)
+	}
+}
+
+func TestMaxSATBuild(t *testing.T) {
+	// Arrange
+	input := softInput(t, Weights{PreferredSlots: 2, LastPeriod: 3, Gaps: 5, CompactDays: 7})
+	timetabler := NewMaxSATTimetabler(branchAndBoundSolver{})
+
+	// Act
+	timetable, cost, err := timetabler.Optimize(input)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.True(t, timetabler.Verify(timetable, input))
+	assert.Equal(t, Cost{CompactDays: 21}, cost) // Only the teaching days of Luciano (two, since Logica is taught once a day) and Dalianys are paid
+}
+
+// Returns an input with the given weights where Luciano, who prefers the first period, teaches Logica to CC-111 twice and Dalianys teaches it
+// Algebra once, in Aula 1 on two days with three periods each
+func softInput(t *testing.T, weights Weights) ModelInput {
+	availability := func() [][]bool { return [][]bool{{true, true}, {true, true}, {true, true}} }
+	input, err := processRawInput(rawModelInput{
+		Subjects: []Subject{{Id: 0, Name: "Logica"}, {Id: 1, Name: "Algebra"}},
+		Professors: []Professor{
+			{Id: 0, Name: "Luciano", Availability: availability(), Preferred: [][]bool{{true, true}, {false, false}, {false, false}}},
+			{Id: 1, Name: "Dalianys", Availability: availability()},
+		},
+		Classes: []rawClass{{Id: 0, Name: "CC-111", Size: 30}},
+		Rooms:   []Room{{Id: 0, Name: "Aula 1", Capacity: 50}},
+		Entries: []rawEntry{
+			{Subject: 0, Professor: 0, Classes: []uint64{0}, Lessons: 2, Permissibility: availability(), Rooms: []uint64{0}},
+			{Subject: 1, Professor: 1, Classes: []uint64{0}, Lessons: 1, Permissibility: availability(), Rooms: []uint64{0}},
+		},
+		Weights: weights,
+	})
+	assert.Nil(t, err)
+	return input
+}
@@ -0,0 +1,187 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/samber/lo"
+)
+
+// Partition splits the entries of an input into ordered stages, where entries matching no stage are left to an extra last one
+type Partition func(modelInput ModelInput) [][][2]uint64
+
+// Returns the partition placing each entry in the stage of its tag
+func PartitionByTag(tags []string) Partition {
+	return func(modelInput ModelInput) [][][2]uint64 {
+		return partition(modelInput, len(tags), func(entryKey [2]uint64) int {
+			return slices.Index(tags, modelInput.Entries[entryKey].Tag)
+		})
+	}
+}
+
+// Returns the partition placing each entry in the stage of the first prefix matching the name of some class of its group (e.g. "CC-1" for freshmen)
+func PartitionByClassPrefix(prefixes []string) Partition {
+	return func(modelInput ModelInput) [][][2]uint64 {
+		return partition(modelInput, len(prefixes), func(entryKey [2]uint64) int {
+			return slices.IndexFunc(prefixes, func(prefix string) bool {
+				return lo.SomeBy(modelInput.Groups[entryKey[1]].Classes, func(class uint64) bool {
+					return strings.HasPrefix(modelInput.Classes[class].Name, prefix)
+				})
+			})
+		})
+	}
+}
+
+// Places the entries in the given number of stages according to the stage function, where entries whose stage is -1 are left to an extra last one
+func partition(modelInput ModelInput, stages int, stage func(entryKey [2]uint64) int) [][][2]uint64 {
+	partition := make([][][2]uint64, stages+1)
+	for _, entryKey := range sortedEntryKeys(modelInput.Entries) {
+		if index := stage(entryKey); index >= 0 {
+			partition[index] = append(partition[index], entryKey)
+		} else {
+			partition[stages] = append(partition[stages], entryKey)
+		}
+	}
+	return partition
+}
+
+type stagedTimetabler struct {
+	timetabler Timetabler
+	partition  Partition
+	backtracks uint64
+}
+
+// Returns a timetabler that builds the stages of the partition one after another with the given timetabler (which must be an embedded-room or
+// isolated-room one), pinning the assignments of the previous stages. Whenever a stage is unsatisfiable, the previous one is rebuilt with another
+// arrangement of its own lessons (i.e. backtracking), at most backtracks times (0 means no limit). Backtracking is complete for embedded-room
+// timetablers only: isolated-room ones assign rooms after solving, so the rooms of a stage are never retried and a satisfiable instance may be
+// reported unsatisfiable (i.e. a nil timetable) when a later stage only fits with other rooms for the previous ones
+func NewStagedTimetabler(timetabler Timetabler, partition Partition, backtracks uint64) Timetabler {
+	return &stagedTimetabler{
+		timetabler: timetabler,
+		partition:  partition,
+		backtracks: backtracks,
+	}
+}
+
+func (timetabler *stagedTimetabler) Build(modelInput ModelInput) (timetable [][6]uint64, variables uint64, clauses uint64, err error) {
+	encoder, ok := timetabler.timetabler.(encoder)
+	if !ok {
+		return nil, 0, 0, errors.New("the timetabler does not support staged building")
+	}
+	stages := normalizeStages(modelInput, timetabler.partition(modelInput))
+
+	// Stage being built along with the timetable (of its own and the previous stages' entries) it was last built with
+	type level struct {
+		encoding   encoding
+		component  component
+		projection map[[4]uint64]int64
+		timetable  [][6]uint64
+	}
+	levels := make([]level, 0, len(stages))
+	backtracks := uint64(0)
+	for stage := 0; stage < len(stages); {
+		if stage == len(levels) {
+			var previous [][6]uint64
+			if stage > 0 {
+				previous = levels[stage-1].timetable
+			}
+			component := stageComponent(modelInput, stages, stage, previous)
+			encoding := encoder.encode(component.modelInput)
+			levels = append(levels, level{encoding: encoding, component: component, projection: encoding.timeProjection()})
+		}
+		current := &levels[stage]
+
+		solution, timetable, err := current.encoding.solve(encoder.satSolver())
+		if err != nil {
+			return nil, 0, 0, err
+		} else if solution == nil { // Backtrack to the previous stage, whose last arrangement is already blocked
+			levels = levels[:stage]
+			if stage == 0 {
+				return nil, 0, 0, nil
+			} else if backtracks++; timetabler.backtracks > 0 && backtracks > timetabler.backtracks {
+				return nil, 0, 0, fmt.Errorf("staged building gave up after %d backtracks", timetabler.backtracks)
+			}
+			stage--
+			continue
+		}
+		current.timetable = current.component.merge(timetable)
+
+		// Block the arrangement of the stage's own lessons, so that backtracking rebuilds the stage with another one. Their rooms are blocked along with
+		// their periods and days if the scheduling variables determine them; otherwise rooms are assigned after solving, thus arrangements differing
+		// only in rooms are not retried
+		own := lo.SliceToMap(stages[stage], func(entryKey [2]uint64) ([2]uint64, bool) { return current.component.local(entryKey), true })
+		block := make([]int64, 0)
+		for _, positive := range timetable {
+			period, day, subjectProfessor, group, room := positive[0], positive[1], positive[3], positive[4], positive[5]
+			if !own[[2]uint64{subjectProfessor, group}] {
+				continue
+			} else if current.encoding.rooms {
+				block = append(block, -current.encoding.roomProjection(period, day, subjectProfessor, group, room))
+			} else {
+				block = append(block, -current.projection[[4]uint64{subjectProfessor, group, period, day}])
+			}
+		}
+		slices.Sort(block)
+		current.encoding.satInstance.Clauses = append(current.encoding.satInstance.Clauses, block)
+		stage++
+	}
+
+	for _, level := range levels {
+		variables, clauses = variables+level.encoding.satInstance.Variables, clauses+uint64(len(level.encoding.satInstance.Clauses))
+	}
+	return levels[len(levels)-1].timetable, variables, clauses, nil
+}
+
+func (timetabler *stagedTimetabler) Verify(timetable [][6]uint64, modelInput ModelInput) bool {
+	return timetabler.timetabler.Verify(timetable, modelInput)
+}
+
+// Moves the entries of every pool and simultaneous group to the earliest stage any of them belongs to (since they cannot be built apart), dropping
+// the stages left empty
+func normalizeStages(modelInput ModelInput, stages [][][2]uint64) [][][2]uint64 {
+	stageOf := make(map[[2]uint64]int)
+	for stage, entryKeys := range stages {
+		for _, entryKey := range entryKeys {
+			stageOf[entryKey] = stage
+		}
+	}
+	for _, entryKeys := range append(slices.Clone(modelInput.Pools), modelInput.Simultaneous...) {
+		earliest := lo.Min(lo.Map(entryKeys, func(entryKey [2]uint64, _ int) int { return stageOf[entryKey] }))
+		for _, entryKey := range entryKeys {
+			stageOf[entryKey] = earliest
+		}
+	}
+
+	normalized := make([][][2]uint64, len(stages))
+	for _, entryKey := range sortedEntryKeys(stageOf) {
+		normalized[stageOf[entryKey]] = append(normalized[stageOf[entryKey]], entryKey)
+	}
+	return lo.Filter(normalized, func(entryKeys [][2]uint64, _ int) bool { return len(entryKeys) > 0 })
+}
+
+// Returns the component made of the entries of the given stage and the previous ones, where the latter are pinned to their positives in the previous
+// timetable. Minimum loads are only enforced at the last stage, since professors may still teach entries of later stages
+func stageComponent(modelInput ModelInput, stages [][][2]uint64, stage int, previous [][6]uint64) component {
+	input := modelInput
+	input.Pinned = slices.Clone(modelInput.Pinned)
+	for _, positive := range previous {
+		input.Pinned = append(input.Pinned, Assignment{
+			SubjectProfessor: positive[3],
+			Group:            positive[4],
+			Lesson:           Any,
+			Day:              positive[1],
+			Period:           positive[0],
+			Room:             positive[5],
+		})
+	}
+	if stage < len(stages)-1 {
+		input.Professors = lo.Map(modelInput.Professors, func(professor Professor, _ int) Professor {
+			professor.MinLoad = 0
+			return professor
+		})
+	}
+	return newComponent(input, lo.Flatten(stages[:stage+1]))
+}
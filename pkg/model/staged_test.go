@@ -0,0 +1,95 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/limaJavier/timetabling/pkg/sat"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStages(t *testing.T) {
+	// Arrange
+	availability := func() [][]bool { return [][]bool{{true, true}, {true, true}} }
+	input, err := processRawInput(rawModelInput{ // Luciano and Dalianys teach CC-111 and CC-112 twice each on two days with two periods each
+		Subjects: []Subject{{Id: 0, Name: "Logica"}, {Id: 1, Name: "Algebra"}},
+		Professors: []Professor{
+			{Id: 0, Name: "Luciano", Availability: availability(), MinLoad: 2},
+			{Id: 1, Name: "Dalianys", Availability: availability()},
+		},
+		Classes: []rawClass{{Id: 0, Name: "CC-111", Size: 30}, {Id: 1, Name: "CC-112", Size: 30}},
+		Rooms:   []Room{{Id: 0, Name: "Aula 1", Capacity: 50}, {Id: 1, Name: "Aula 2", Capacity: 50}},
+		Entries: []rawEntry{
+			{Subject: 0, Professor: 0, Classes: []uint64{0}, Lessons: 2, Permissibility: availability(), Rooms: []uint64{0, 1}, Tag: "second"},
+			{Subject: 1, Professor: 1, Classes: []uint64{1}, Lessons: 2, Permissibility: availability(), Rooms: []uint64{0, 1}},
+		},
+	})
+	assert.Nil(t, err)
+
+	// Act
+	byPrefix := normalizeStages(input, PartitionByClassPrefix([]string{"CC-112", "CC-111"})(input))
+	byTag := normalizeStages(input, PartitionByTag([]string{"first", "second"})(input))
+	first := stageComponent(input, byPrefix, 0, nil)
+	last := stageComponent(input, byPrefix, 1, [][6]uint64{{0, 1, 0, 1, 1, 0}, {1, 0, 1, 1, 1, 1}})
+
+	// Assert
+	assert.Equal(t, [][][2]uint64{{{1, 1}}, {{0, 0}}}, byPrefix)
+	assert.Equal(t, [][][2]uint64{{{0, 0}}, {{1, 1}}}, byTag) // The first stage is empty, and the untagged entry is left to the last one
+	assert.Len(t, first.modelInput.Entries, 1)
+	assert.Empty(t, first.modelInput.Pinned)
+	assert.Equal(t, uint64(0), first.modelInput.Professors[0].MinLoad) // Professors may still teach entries of later stages
+	assert.Len(t, last.modelInput.Entries, 2)
+	assert.Equal(t, uint64(2), last.modelInput.Professors[0].MinLoad)
+	assert.Equal(t, []Assignment{
+		{SubjectProfessor: 1, Group: 1, Lesson: Any, Day: 1, Period: 0, Room: 0},
+		{SubjectProfessor: 1, Group: 1, Lesson: Any, Day: 0, Period: 1, Room: 1},
+	}, last.modelInput.Pinned)
+	assert.Equal(t, [2]uint64{1, 1}, last.local([2]uint64{1, 1}))
+}
+
+// SAT solver counting the unsatisfiable instances it is given
+type countingSolver struct {
+	solver        sat.SATSolver
+	unsatisfiable int
+}
+
+func (solver *countingSolver) Solve(instance sat.SAT) (sat.SATSolution, error) {
+	solution, err := solver.solver.Solve(instance)
+	if err == nil && solution == nil {
+		solver.unsatisfiable++
+	}
+	return solution, err
+}
+
+func TestStagedBacktracking(t *testing.T) {
+	// The first stage's entry may take either room, whereas the second stage's entry needs one of them at the only slot. Whichever room the first
+	// stage takes first, one of the scenarios finds the second stage unsatisfiable and must rebuild the first stage in the other room
+	unsatisfiable := 0
+	for _, room := range []uint64{0, 1} {
+		// Arrange
+		input, err := processRawInput(rawModelInput{ // Luciano teaches Logica to CC-111 and Dalianys teaches Algebra to CC-112, in a single slot
+			Subjects: []Subject{{Id: 0, Name: "Logica"}, {Id: 1, Name: "Algebra"}},
+			Professors: []Professor{
+				{Id: 0, Name: "Luciano", Availability: [][]bool{{true}}},
+				{Id: 1, Name: "Dalianys", Availability: [][]bool{{true}}},
+			},
+			Classes: []rawClass{{Id: 0, Name: "CC-111", Size: 30}, {Id: 1, Name: "CC-112", Size: 30}},
+			Rooms:   []Room{{Id: 0, Name: "Aula 1", Capacity: 50}, {Id: 1, Name: "Aula 2", Capacity: 50}},
+			Entries: []rawEntry{
+				{Subject: 0, Professor: 0, Classes: []uint64{0}, Lessons: 1, Permissibility: [][]bool{{true}}, Rooms: []uint64{0, 1}, Tag: "first"},
+				{Subject: 1, Professor: 1, Classes: []uint64{1}, Lessons: 1, Permissibility: [][]bool{{true}}, Rooms: []uint64{room}, Tag: "second"},
+			},
+		})
+		assert.Nil(t, err)
+		solver := &countingSolver{solver: dpllSolver{}}
+		timetabler := NewStagedTimetabler(NewEmbeddedRoomTimetabler(solver), PartitionByTag([]string{"first", "second"}), 0)
+
+		// Act
+		timetable, _, _, err := timetabler.Build(input)
+
+		// Assert
+		assert.Nil(t, err)
+		assert.True(t, verify(timetable, input), "room = %d", room)
+		unsatisfiable += solver.unsatisfiable
+	}
+	assert.Positive(t, unsatisfiable)
+}
@@ -0,0 +1,48 @@
+package model
+
+import (
+	"errors"
+
+	"github.com/limaJavier/timetabling/pkg/sat"
+)
+
+type symmetryBreakingTimetabler struct {
+	encoder    encoder
+	timetabler Timetabler
+}
+
+// Returns a timetabler that extends the SAT instance of the given one (which must be an embedded-room or isolated-room timetabler) by ordering the
+// interchangeable lessons of each entry and, if rooms are part of the instance, the interchangeable rooms (see lessonSymmetryConstraints and
+// roomSymmetryConstraints), which shrinks the search space without losing any timetable up to those symmetries
+func WithSymmetryBreaking(timetabler Timetabler) (Timetabler, error) {
+	encoder, ok := timetabler.(encoder)
+	if !ok {
+		return nil, errors.New("the timetabler does not support symmetry breaking")
+	}
+	return &symmetryBreakingTimetabler{
+		encoder:    encoder,
+		timetabler: timetabler,
+	}, nil
+}
+
+func (timetabler *symmetryBreakingTimetabler) Build(modelInput ModelInput) (timetable [][6]uint64, variables uint64, clauses uint64, err error) {
+	return buildEncoded(timetabler, modelInput)
+}
+
+func (timetabler *symmetryBreakingTimetabler) encode(modelInput ModelInput) encoding {
+	encoding := timetabler.encoder.encode(modelInput)
+	encoding.satInstance.Clauses = append(encoding.satInstance.Clauses, lessonSymmetryConstraints(encoding.state)...)
+	if encoding.rooms {
+		encoding.satInstance.Clauses = append(encoding.satInstance.Clauses, roomSymmetryConstraints(encoding.state)...)
+	}
+	encoding.satInstance.Variables = encoding.state.allocator.Last()
+	return encoding
+}
+
+func (timetabler *symmetryBreakingTimetabler) satSolver() sat.SATSolver {
+	return timetabler.encoder.satSolver()
+}
+
+func (timetabler *symmetryBreakingTimetabler) Verify(timetable [][6]uint64, modelInput ModelInput) bool {
+	return timetabler.timetabler.Verify(timetable, modelInput)
+}
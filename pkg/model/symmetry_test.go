@@ -0,0 +1,49 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithSymmetryBreaking(t *testing.T) {
+	scenarios := []struct {
+		name       string
+		timetabler Timetabler
+		supported  bool
+	}{
+		{"embedded-room", NewEmbeddedRoomTimetabler(dpllSolver{}), true},
+		{"isolated-room", NewIsolatedRoomTimetabler(dpllSolver{}, false, 0), true},
+		{"iterative", NewIterativeTimetabler(dpllSolver{}, ObjectiveGaps, LinearSearch, time.Minute), false},
+	}
+
+	for _, scenario := range scenarios {
+		// Arrange
+		input, err := processRawInput(rawModelInput{ // Luciano teaches Logica to CC-111 twice in either classroom, on two days with two periods each
+			Subjects:   []Subject{{Id: 0, Name: "Logica"}},
+			Professors: []Professor{{Id: 0, Name: "Luciano", Availability: [][]bool{{true, true}, {true, true}}}},
+			Classes:    []rawClass{{Id: 0, Name: "CC-111", Size: 30}},
+			Rooms:      []Room{{Id: 0, Name: "Aula 1", Capacity: 50}, {Id: 1, Name: "Aula 2", Capacity: 50}},
+			Entries: []rawEntry{
+				{Subject: 0, Professor: 0, Classes: []uint64{0}, Lessons: 2, Permissibility: [][]bool{{true, true}, {true, true}}, Rooms: []uint64{0, 1}},
+			},
+		})
+		assert.Nil(t, err)
+
+		// Act
+		timetabler, err := WithSymmetryBreaking(scenario.timetabler)
+
+		// Assert
+		if !scenario.supported {
+			assert.NotNil(t, err, scenario.name)
+			continue
+		}
+		assert.Nil(t, err, scenario.name)
+		timetable, _, clauses, err := timetabler.Build(input)
+		assert.Nil(t, err, scenario.name)
+		assert.True(t, timetabler.Verify(timetable, input), scenario.name)
+		_, _, unbrokenClauses, _ := scenario.timetabler.Build(input)
+		assert.Greater(t, clauses, unbrokenClauses, scenario.name) // The symmetry-breaking clauses are added
+	}
+}
@@ -10,3 +10,22 @@ type Timetabler interface {
 		modelInput ModelInput,
 	) bool
 }
+
+// Optimizer is a timetabler that builds timetables minimizing the cost of the soft constraints
+type Optimizer interface {
+	Timetabler
+
+	Optimize(
+		modelInput ModelInput,
+	) (timetable [][6]uint64, cost Cost, err error)
+}
+
+// Rebuilder is an optimizer that rebuilds a previous timetable for a (possibly edited) input, keeping as many of its assignments as possible
+type Rebuilder interface {
+	Optimizer
+
+	Rebuild(
+		previous [][6]uint64,
+		modelInput ModelInput,
+	) (timetable [][6]uint64, diff TimetableDiff, err error)
+}
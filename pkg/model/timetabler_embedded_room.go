@@ -13,6 +13,33 @@ func NewEmbeddedRoomTimetabler(solver sat.SATSolver) Timetabler {
 }
 
 func (timetabler *embeddedRoomTimetabler) Build(modelInput ModelInput) (timetable [][6]uint64, variables uint64, clauses uint64, err error) {
+	return buildEncoded(timetabler, modelInput)
+}
+
+func (timetabler *embeddedRoomTimetabler) encode(modelInput ModelInput) encoding {
+	state := newEmbeddedRoomState(modelInput)
+	satInstance, explicitVariables := buildSat(state.schedulingVariables(), embeddedRoomConstraints(), state)
+
+	return encoding{
+		satInstance: satInstance,
+		state:       state,
+		decode: func(solution sat.SATSolution) ([][6]uint64, error) {
+			return decodeEmbeddedRoomSolution(solution, explicitVariables, state.indexer), nil
+		},
+		rooms: true,
+	}
+}
+
+func (timetabler *embeddedRoomTimetabler) satSolver() sat.SATSolver {
+	return timetabler.solver
+}
+
+func (timetabler *embeddedRoomTimetabler) Verify(timetable [][6]uint64, modelInput ModelInput) bool {
+	return verify(timetable, modelInput)
+}
+
+// Returns the constraint state of the embedded-room strategy, where the allocator hands out auxiliary variables right after the scheduling ones
+func newEmbeddedRoomState(modelInput ModelInput) constraintState {
 	//** Extract attributes's domains
 	totalPeriods, totalDays, totalLessons, totalSubjectProfessors, totalGroups, totalRooms := getAttributes(modelInput)
 
@@ -21,27 +48,16 @@ func (timetabler *embeddedRoomTimetabler) Build(modelInput ModelInput) (timetabl
 	indexer := newIndexer(totalPeriods, totalDays, totalLessons, totalSubjectProfessors, totalGroups, totalRooms)
 	generator := newPermutationGenerator(totalPeriods, totalDays, totalLessons, totalSubjectProfessors, totalGroups, totalRooms)
 
-	//** Build SAT instance
-	variables = totalPeriods * totalDays * totalLessons * totalSubjectProfessors * totalGroups * totalRooms
-
-	// Constraints functions
-	constraints := []func(state constraintState) [][]int64{
-		professorConstraints,
-		studentConstraints,
-		subjectPermissibilityConstraints,
-		professorAvailabilityConstraints,
-		lessonConstraints,
-		roomConstraints,
-		roomNegationConstraints,
-		completenessConstraints,
-		negationConstraints,
-		uniquenessConstraints,
-	}
+	variables := totalPeriods * totalDays * totalLessons * totalSubjectProfessors * totalGroups * totalRooms
+	allocator := newVariableAllocator(variables)
 
-	state := constraintState{
+	return constraintState{
+		modelInput:        modelInput,
 		evaluator:         evaluator,
 		indexer:           indexer,
 		generator:         generator,
+		allocator:         allocator,
+		choices:           newChoiceVariables(modelInput, allocator),
 		periods:           totalPeriods,
 		days:              totalDays,
 		lessons:           totalLessons,
@@ -49,30 +65,42 @@ func (timetabler *embeddedRoomTimetabler) Build(modelInput ModelInput) (timetabl
 		groups:            totalGroups,
 		rooms:             totalRooms,
 	}
+}
 
-	satInstance, explicitVariables := buildSat(variables, constraints, state)
-
-	//** Solve SAT instance
-	solution, err := timetabler.solver.Solve(satInstance)
-	if err != nil {
-		return nil, 0, 0, err
-	} else if solution == nil { // Return nil if the SAT instance is not satisfiable
-		return nil, variables, uint64(len(satInstance.Clauses)), nil
+// Returns the constraints functions of the embedded-room strategy
+func embeddedRoomConstraints() []func(state constraintState) [][]int64 {
+	return []func(state constraintState) [][]int64{
+		professorConstraints,
+		studentConstraints,
+		subjectPermissibilityConstraints,
+		professorAvailabilityConstraints,
+		roomAvailabilityConstraints,
+		lessonConstraints,
+		roomConstraints,
+		roomNegationConstraints,
+		completenessConstraints,
+		negationConstraints,
+		uniquenessConstraints,
+		pinnedConstraints,
+		forbiddenConstraints,
+		simultaneityConstraints,
+		teachingDaysConstraints,
+		professorChoiceConstraints,
+		weeklyLessonsConstraints,
+		travelConstraints,
+		roomStabilityConstraints,
 	}
+}
 
-	timetable = [][6]uint64{}
+// Decodes the timetable from the solution by acknowledging only positive variables that are explicitly stated in the clauses
+func decodeEmbeddedRoomSolution(solution sat.SATSolution, explicitVariables map[int64]bool, indexer indexer) [][6]uint64 {
+	timetable := [][6]uint64{}
 	for _, variable := range solution {
-		// Acknowledge only positive variables that are explicitly stated in the clauses
 		if variable > 0 && explicitVariables[variable] {
 			positive := [6]uint64{}
 			positive[0], positive[1], positive[2], positive[3], positive[4], positive[5] = indexer.Attributes(uint64(variable))
 			timetable = append(timetable, positive)
 		}
 	}
-
-	return timetable, variables, uint64(len(satInstance.Clauses)), nil
-}
-
-func (timetabler *embeddedRoomTimetabler) Verify(timetable [][6]uint64, modelInput ModelInput) bool {
-	return verify(timetable, modelInput)
+	return timetable
 }
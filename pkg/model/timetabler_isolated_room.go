@@ -1,11 +1,17 @@
 package model
 
 import (
+	"errors"
+
 	"github.com/limaJavier/timetabling/pkg/sat"
 
 	"github.com/samber/lo"
 )
 
+// Rooms are assigned after solving, which ignores whether the next lesson's room can be reached during the break, so the timetables would not
+// pass verify
+var errTravelTimes = errors.New("travel times cannot be taken into account when rooms are assigned after solving")
+
 type isolatedRoomTimetabler struct {
 	solver                  sat.SATSolver
 	hybrid                  bool
@@ -21,6 +27,13 @@ func NewIsolatedRoomTimetabler(solver sat.SATSolver, hybrid bool, roomSimilarity
 }
 
 func (timetabler *isolatedRoomTimetabler) Build(modelInput ModelInput) (timetable [][6]uint64, variables uint64, clauses uint64, err error) {
+	if len(modelInput.TravelTimes) > 0 {
+		return nil, 0, 0, errTravelTimes
+	}
+	return buildEncoded(timetabler, modelInput)
+}
+
+func (timetabler *isolatedRoomTimetabler) encode(modelInput ModelInput) encoding {
 	//** Extract attributes's domains
 	totalRooms := uint64(1)
 	totalPeriods, totalDays, totalLessons, totalSubjectProfessors, totalGroups, _ := getAttributes(modelInput)
@@ -32,7 +45,7 @@ func (timetabler *isolatedRoomTimetabler) Build(modelInput ModelInput) (timetabl
 	generator := newPermutationGenerator(totalPeriods, totalDays, totalLessons, totalSubjectProfessors, totalGroups, totalRooms)
 
 	//** Build SAT instance
-	variables = totalPeriods * totalDays * totalLessons * totalSubjectProfessors * totalGroups * totalRooms
+	variables := totalPeriods * totalDays * totalLessons * totalSubjectProfessors * totalGroups * totalRooms
 
 	// Constraints functions
 	constraints := []func(state constraintState) [][]int64{
@@ -44,15 +57,25 @@ func (timetabler *isolatedRoomTimetabler) Build(modelInput ModelInput) (timetabl
 		completenessConstraints,
 		negationConstraints,
 		uniquenessConstraints,
+		pinnedConstraints,
+		forbiddenConstraints,
+		simultaneityConstraints,
+		teachingDaysConstraints,
+		professorChoiceConstraints,
+		weeklyLessonsConstraints,
 	}
 	if timetabler.hybrid {
 		constraints = append(constraints, roomSimilarityConstraints)
 	}
 
+	allocator := newVariableAllocator(variables)
 	state := constraintState{
+		modelInput:        modelInput,
 		evaluator:         isolatedEvaluator,
 		indexer:           indexer,
 		generator:         generator,
+		allocator:         allocator,
+		choices:           newChoiceVariables(modelInput, allocator),
 		periods:           totalPeriods,
 		days:              totalDays,
 		lessons:           totalLessons,
@@ -63,21 +86,42 @@ func (timetabler *isolatedRoomTimetabler) Build(modelInput ModelInput) (timetabl
 
 	satInstance, explicitVariables := buildSat(variables, constraints, state)
 
-	//** Solve SAT instance
-	solution, err := timetabler.solver.Solve(satInstance)
-	if err != nil {
-		return nil, 0, 0, err
-	} else if solution == nil { // Return nil if the SAT instance is not satisfiable
-		return nil, variables, uint64(len(satInstance.Clauses)), nil
-	}
+	// Hard room stability binds scopes to rooms, which the room assignment must respect
+	stable, stabilityClauses := roomStabilityVariables(state, false)
+	satInstance.Clauses = append(satInstance.Clauses, stabilityClauses...)
+	satInstance.Variables = state.allocator.Last()
 
-	// Filter solution by taking only positive and explicit variables
-	solution = lo.Filter(solution, func(variable int64, _ int) bool {
-		return variable > 0 && explicitVariables[variable]
-	})
+	return encoding{
+		satInstance: satInstance,
+		state:       state,
+		decode: func(solution sat.SATSolution) ([][6]uint64, error) {
+			if len(modelInput.TravelTimes) > 0 { // Reached when the encoding is solved without Build (e.g. by enumeration or staged building)
+				return nil, errTravelTimes
+			}
+
+			// Find the rooms the scopes are bound to
+			trueVariables := lo.SliceToMap(solution, func(variable int64) (int64, bool) { return variable, true })
+			bound := make(map[[3]uint64]boundRoom)
+			for scope, rooms := range stable {
+				for room, variable := range rooms {
+					if trueVariables[variable] {
+						bound[scope] = boundRoom{room: room, variable: variable}
+					}
+				}
+			}
+
+			// Filter solution by taking only positive and explicit variables
+			solution = lo.Filter(solution, func(variable int64, _ int) bool {
+				return variable > 0 && explicitVariables[variable]
+			})
+
+			return roomAssignment(solution, bound, indexer, standardEvaluator, modelInput)
+		},
+	}
+}
 
-	timetable, err = roomAssignment(solution, indexer, standardEvaluator, modelInput)
-	return timetable, variables, uint64(len(satInstance.Clauses)), err
+func (timetabler *isolatedRoomTimetabler) satSolver() sat.SATSolver {
+	return timetabler.solver
 }
 
 func (timetabler *isolatedRoomTimetabler) Verify(timetable [][6]uint64, modelInput ModelInput) bool {
@@ -0,0 +1,199 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/limaJavier/timetabling/pkg/sat"
+)
+
+// Objective minimized by the iterative optimizer, i.e. the number of violations of a single soft constraint
+type Objective uint64
+
+const (
+	ObjectiveGaps          Objective = iota // Idle periods between two lessons of a class on the same day
+	ObjectiveDislikedSlots                  // Lessons taught at slots their professor dislikes
+	ObjectiveLatePeriods                    // Lessons taught at the last period of the day
+)
+
+// Returns the unit weights that enable only the soft constraint associated to the objective
+func (objective Objective) Weights() Weights {
+	switch objective {
+	case ObjectiveGaps:
+		return Weights{Gaps: 1}
+	case ObjectiveDislikedSlots:
+		return Weights{DislikedSlots: 1}
+	default:
+		return Weights{LastPeriod: 1}
+	}
+}
+
+// Strategy used by the iterative optimizer to tighten the objective's bound
+type Search uint64
+
+const (
+	LinearSearch Search = iota // Bound the objective below the best cost found until the instance is unsatisfiable
+	BinarySearch               // Bisect the interval between the greatest known infeasible bound and the best cost found
+)
+
+type iterativeTimetabler struct {
+	solver    sat.SATSolver
+	objective Objective
+	search    Search
+	budget    time.Duration
+}
+
+// Returns an optimizer based on the embedded-room strategy that minimizes the objective by repeatedly calling the SAT solver with a tightening totalizer bound. If the budget (0 means no limit) expires, the best timetable found so far is returned
+func NewIterativeTimetabler(solver sat.SATSolver, objective Objective, search Search, budget time.Duration) Rebuilder {
+	return &iterativeTimetabler{
+		solver:    solver,
+		objective: objective,
+		search:    search,
+		budget:    budget,
+	}
+}
+
+func (timetabler *iterativeTimetabler) Build(modelInput ModelInput) (timetable [][6]uint64, variables uint64, clauses uint64, err error) {
+	objectiveInput := modelInput
+	objectiveInput.Weights = timetabler.objective.Weights()
+	measure := func(timetable [][6]uint64) uint64 { return Evaluate(timetable, objectiveInput).Total() }
+	return timetabler.optimize(modelInput, weightedSoftConstraints(softConstraints(), objectiveInput.Weights), measure)
+}
+
+func (timetabler *iterativeTimetabler) Optimize(modelInput ModelInput) (timetable [][6]uint64, cost Cost, err error) {
+	timetable, _, _, err = timetabler.Build(modelInput)
+	if err != nil || timetable == nil {
+		return nil, Cost{}, err
+	}
+	objectiveInput := modelInput
+	objectiveInput.Weights = timetabler.objective.Weights()
+	return timetable, Evaluate(timetable, objectiveInput), nil
+}
+
+// Rebuilds the timetable minimizing the number of lost previous assignments
+func (timetabler *iterativeTimetabler) Rebuild(previous [][6]uint64, modelInput ModelInput) (timetable [][6]uint64, diff TimetableDiff, err error) {
+	keepable := keepableAssignments(previous, modelInput)
+	measure := func(timetable [][6]uint64) uint64 { return uint64(len(lostAssignments(keepable, timetable))) }
+	timetable, _, _, err = timetabler.optimize(modelInput, []softConstraint{previousAssignmentsSoftConstraints(keepable, 1)}, measure)
+	if err != nil || timetable == nil {
+		return nil, TimetableDiff{}, err
+	}
+	return timetable, Diff(previous, timetable, modelInput), nil
+}
+
+func (timetabler *iterativeTimetabler) Verify(timetable [][6]uint64, modelInput ModelInput) bool {
+	return verify(timetable, modelInput)
+}
+
+// Minimizes the number of violated soft clauses, given by measure for every timetable found, by repeatedly calling the SAT solver with a tightening totalizer bound
+func (timetabler *iterativeTimetabler) optimize(modelInput ModelInput, constraints []softConstraint, measure func(timetable [][6]uint64) uint64) (timetable [][6]uint64, variables uint64, clauses uint64, err error) {
+	// The context is done once the budget expires, which kills the running solver (if it supports cancellation)
+	ctx, cancel := context.WithCancel(context.Background())
+	if timetabler.budget > 0 {
+		ctx, cancel = context.WithTimeout(context.Background(), timetabler.budget)
+	}
+	defer cancel()
+
+	//** Build SAT instance along with the objective's totalizer
+	state := newEmbeddedRoomState(modelInput)
+	satInstance, explicitVariables := buildSat(state.schedulingVariables(), embeddedRoomConstraints(), state)
+	objectiveClauses, penalties := objectiveLiterals(state, constraints)
+	totalizerClauses, outputs := totalizer(penalties, state.allocator)
+	satInstance.Clauses = append(append(satInstance.Clauses, objectiveClauses...), totalizerClauses...)
+	satInstance.Variables = state.allocator.Last()
+	variables, clauses = satInstance.Variables, uint64(len(satInstance.Clauses))
+
+	// Solves the instance bounding the objective to at most bound violations (math.MaxUint64 means unbounded)
+	type result struct {
+		timetable [][6]uint64
+		err       error
+	}
+	solve := func(bound uint64) (result, bool) {
+		boundedInstance := satInstance
+		if bound < uint64(len(outputs)) {
+			boundedInstance.Clauses = append(slices.Clone(satInstance.Clauses), []int64{-outputs[bound]})
+		}
+
+		results := make(chan result, 1)
+		go func() {
+			var solution sat.SATSolution
+			var err error
+			if solver, ok := timetabler.solver.(sat.ContextSATSolver); ok {
+				solution, err = solver.SolveContext(ctx, boundedInstance)
+			} else {
+				solution, err = timetabler.solver.Solve(boundedInstance) // Solvers without cancellation run until they finish and their result is discarded
+			}
+			if err != nil || solution == nil {
+				results <- result{err: err}
+				return
+			}
+			results <- result{timetable: decodeEmbeddedRoomSolution(solution, explicitVariables, state.indexer)}
+		}()
+
+		select {
+		case solved := <-results:
+			if ctx.Err() != nil { // The budget expired while the solver was being killed
+				return result{}, false
+			}
+			return solved, true
+		case <-ctx.Done():
+			return result{}, false
+		}
+	}
+
+	//** Find a first feasible timetable
+	first, ok := solve(math.MaxUint64)
+	if !ok {
+		return nil, variables, clauses, fmt.Errorf("time budget of %v expired before a timetable was found", timetabler.budget)
+	} else if first.err != nil || first.timetable == nil {
+		return nil, variables, clauses, first.err
+	}
+	timetable = first.timetable
+	best := measure(timetable)
+
+	//** Tighten the bound until the instance becomes unsatisfiable or the budget expires
+	lower := uint64(0) // Smallest bound that may still be satisfiable
+	for lower < best {
+		bound := best - 1
+		if timetabler.search == BinarySearch {
+			bound = lower + (best-lower)/2
+		}
+
+		current, ok := solve(bound)
+		if !ok {
+			break
+		} else if current.err != nil {
+			return nil, variables, clauses, current.err
+		} else if current.timetable == nil {
+			lower = bound + 1
+			continue
+		}
+		timetable = current.timetable
+		best = measure(timetable)
+	}
+
+	return timetable, variables, clauses, nil
+}
+
+// Returns the clauses defining the objective's auxiliary variables along with the literals whose truth accounts for one violation each
+func objectiveLiterals(state constraintState, constraints []softConstraint) ([][]int64, []int64) {
+	clauses, penalties := make([][]int64, 0), make([]int64, 0)
+	for _, constraint := range constraints {
+		hard, soft := constraint(state)
+		clauses = append(clauses, hard...)
+		for _, clause := range soft {
+			if len(clause.Literals) == 1 {
+				penalties = append(penalties, -clause.Literals[0])
+				continue
+			}
+			// Relax clauses of several literals, so that the relaxation variable is true whenever the clause is violated
+			relaxation := state.allocator.Next()
+			clauses = append(clauses, append(slices.Clone(clause.Literals), relaxation))
+			penalties = append(penalties, relaxation)
+		}
+	}
+	return clauses, penalties
+}
@@ -0,0 +1,70 @@
+package model
+
+import "github.com/limaJavier/timetabling/pkg/sat"
+
+type maxSATTimetabler struct {
+	solver sat.MaxSATSolver
+}
+
+// Returns an optimizer based on the embedded-room strategy, where soft constraints are handled by a MaxSAT solver
+func NewMaxSATTimetabler(solver sat.MaxSATSolver) Rebuilder {
+	return &maxSATTimetabler{
+		solver: solver,
+	}
+}
+
+func (timetabler *maxSATTimetabler) Build(modelInput ModelInput) (timetable [][6]uint64, variables uint64, clauses uint64, err error) {
+	return timetabler.build(modelInput, nil)
+}
+
+func (timetabler *maxSATTimetabler) build(modelInput ModelInput, extend func(maxSatInstance *sat.MaxSAT, state constraintState)) (timetable [][6]uint64, variables uint64, clauses uint64, err error) {
+	//** Build MaxSAT instance
+	state := newEmbeddedRoomState(modelInput)
+	satInstance, explicitVariables := buildSat(state.schedulingVariables(), embeddedRoomConstraints(), state)
+	maxSatInstance := buildMaxSat(satInstance, softConstraints(), state)
+	if extend != nil {
+		extend(&maxSatInstance, state)
+	}
+	variables, clauses = maxSatInstance.Variables, uint64(len(maxSatInstance.Hard)+len(maxSatInstance.Soft))
+
+	//** Solve MaxSAT instance
+	solution, _, err := timetabler.solver.Solve(maxSatInstance)
+	if err != nil {
+		return nil, 0, 0, err
+	} else if solution == nil { // Return nil if the hard clauses are not satisfiable
+		return nil, variables, clauses, nil
+	}
+
+	timetable = decodeEmbeddedRoomSolution(solution, explicitVariables, state.indexer)
+	return timetable, variables, clauses, nil
+}
+
+func (timetabler *maxSATTimetabler) Optimize(modelInput ModelInput) (timetable [][6]uint64, cost Cost, err error) {
+	timetable, _, _, err = timetabler.Build(modelInput)
+	if err != nil || timetable == nil {
+		return nil, Cost{}, err
+	}
+	return timetable, Evaluate(timetable, modelInput), nil
+}
+
+// Rebuilds the timetable minimizing first the number of lost previous assignments and then the cost of the soft constraints
+func (timetabler *maxSATTimetabler) Rebuild(previous [][6]uint64, modelInput ModelInput) (timetable [][6]uint64, diff TimetableDiff, err error) {
+	keepable := keepableAssignments(previous, modelInput)
+	timetable, _, _, err = timetabler.build(modelInput, func(maxSatInstance *sat.MaxSAT, state constraintState) {
+		// Losing an assignment must outweigh any cost of the remaining soft constraints
+		weight := uint64(1)
+		for _, clause := range maxSatInstance.Soft {
+			weight += clause.Weight
+		}
+		_, soft := previousAssignmentsSoftConstraints(keepable, weight)(state)
+		maxSatInstance.Soft = append(maxSatInstance.Soft, soft...)
+	})
+	if err != nil || timetable == nil {
+		return nil, TimetableDiff{}, err
+	}
+	return timetable, Diff(previous, timetable, modelInput), nil
+}
+
+func (timetabler *maxSATTimetabler) Verify(timetable [][6]uint64, modelInput ModelInput) bool {
+	return verify(timetable, modelInput)
+}
@@ -1,12 +1,17 @@
 package model
 
 import (
+	"cmp"
+	"context"
 	"log"
 	"os"
+	"slices"
 	"testing"
+	"time"
 
 	"github.com/limaJavier/timetabling/pkg/sat"
 
+	"github.com/samber/lo"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -78,3 +83,556 @@ func satisfiableExecution(t *testing.T, timetabler Timetabler) {
 		assert.True(t, timetabler.Verify(timetable, input))
 	}
 }
+
+func TestAssignRoomsHallViolator(t *testing.T) {
+	// Arrange
+	variables, rooms := []int64{1, 2, 3}, []uint64{10, 11}
+	relationships := map[[2]uint64]bool{{1, 10}: true, {2, 10}: true, {3, 10}: true, {3, 11}: true} // Variables 1 and 2 share a single room
+
+	// Act
+	assignments, err := assignRooms(variables, rooms, relationships, func(int64, uint64) int64 { return 0 })
+
+	// Assert
+	assert.Nil(t, assignments)
+	assert.Equal(t, unassignableError{violators: [][]int64{{1, 2}}}, err)
+}
+
+func TestAssignRoomsMinimizesCost(t *testing.T) {
+	// Arrange
+	variables, rooms := []int64{1, 2}, []uint64{10, 11, 12}
+	relationships := map[[2]uint64]bool{{1, 10}: true, {1, 11}: true, {1, 12}: true, {2, 10}: true, {2, 11}: true}
+	costs := map[[2]uint64]int64{{1, 10}: 1, {1, 11}: 5, {1, 12}: 3, {2, 10}: 2, {2, 11}: 9}
+
+	// Act
+	assignments, err := assignRooms(variables, rooms, relationships, func(variable int64, room uint64) int64 { return costs[[2]uint64{uint64(variable), room}] })
+
+	// Assert
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, [][2]uint64{{1, 12}, {2, 10}}, assignments)
+}
+
+func TestVerifyRoomAvailability(t *testing.T) {
+	// Arrange
+	input, err := processRawInput(rawModelInput{ // Luciano teaches Logica to CC-111 on two days with a single period, where Aula 2 is booked on day 1
+		Subjects:   []Subject{{Id: 0, Name: "Logica"}},
+		Professors: []Professor{{Id: 0, Name: "Luciano", Availability: [][]bool{{true, true}}}},
+		Classes:    []rawClass{{Id: 0, Name: "CC-111", Size: 30}},
+		Rooms:      []Room{{Id: 0, Name: "Aula 1", Capacity: 50}, {Id: 1, Name: "Aula 2", Capacity: 50, Availability: [][]bool{{true, false}}}},
+		Entries: []rawEntry{
+			{Subject: 0, Professor: 0, Classes: []uint64{0}, Lessons: 2, Permissibility: [][]bool{{true, true}}, Rooms: []uint64{0, 1}},
+		},
+	})
+	assert.Nil(t, err)
+	scenarios := []struct {
+		timetable [][6]uint64
+		valid     bool
+	}{
+		{[][6]uint64{{0, 0, 0, 0, 0, 1}, {0, 1, 1, 0, 0, 0}}, true},
+		{[][6]uint64{{0, 0, 0, 0, 0, 0}, {0, 1, 1, 0, 0, 1}}, false}, // Aula 2 is used while booked
+	}
+
+	for _, scenario := range scenarios {
+		// Act
+		valid := verify(scenario.timetable, input)
+
+		// Assert
+		assert.Equal(t, scenario.valid, valid, "timetable = %v", scenario.timetable)
+	}
+}
+
+func TestVerifyTeamTeaching(t *testing.T) {
+	// Arrange
+	input, err := processRawInput(teamTeachingRawInput())
+	assert.Nil(t, err)
+	scenarios := []struct {
+		timetable [][6]uint64
+		valid     bool
+	}{
+		{[][6]uint64{{0, 0, 0, 0, 0, 0}, {1, 0, 0, 1, 1, 1}}, true},
+		{[][6]uint64{{0, 0, 0, 0, 0, 0}, {0, 0, 0, 1, 1, 1}}, false}, // Dalianys teaches both entries at once
+	}
+
+	for _, scenario := range scenarios {
+		// Act
+		valid := verify(scenario.timetable, input)
+
+		// Assert
+		assert.Equal(t, scenario.valid, valid, "timetable = %v", scenario.timetable)
+	}
+}
+
+func TestVerifyProfessorChoice(t *testing.T) {
+	// Arrange
+	rawInput := candidatesRawInput()
+	rawInput.Professors[1].MinLoad = 2
+	input, err := processRawInput(rawInput)
+	assert.Nil(t, err)
+	algebra := func(professor uint64) uint64 {
+		entryKey, _ := findEntry(input, 1, professor, []uint64{1})
+		return entryKey[0]
+	}
+	scenarios := []struct {
+		subjectProfessors [2]uint64 // Candidate subject-professors teaching Algebra's lessons
+		valid             bool
+	}{
+		{[2]uint64{algebra(1), algebra(1)}, true},
+		{[2]uint64{algebra(0), algebra(0)}, false}, // Dalianys teaches fewer lessons than their minimum load
+		{[2]uint64{algebra(0), algebra(1)}, false}, // Both candidates teach Algebra
+	}
+
+	for _, scenario := range scenarios {
+		// Act
+		valid := verify([][6]uint64{
+			{0, 0, 0, 0, 0, 0},
+			{0, 1, 1, 0, 0, 0},
+			{1, 0, 0, scenario.subjectProfessors[0], 1, 1},
+			{1, 1, 1, scenario.subjectProfessors[1], 1, 1},
+		}, input)
+
+		// Assert
+		assert.Equal(t, scenario.valid, valid, "subject-professors = %v", scenario.subjectProfessors)
+	}
+}
+
+func TestVerifyWeeklyLessons(t *testing.T) {
+	// Arrange
+	input, err := processRawInput(weeksRawInput())
+	assert.Nil(t, err)
+	scenarios := []struct {
+		timetable [][6]uint64
+		valid     bool
+	}{
+		{[][6]uint64{{0, 0, 0, 0, 0, 0}, {0, 0, 0, 1, 1, 1}, {0, 3, 1, 1, 1, 1}}, true},
+		{[][6]uint64{{0, 0, 0, 0, 0, 0}, {0, 0, 0, 1, 1, 1}, {0, 1, 1, 1, 1, 1}}, false}, // Both lessons of Algebra are taught in the first week
+		{[][6]uint64{{0, 2, 0, 0, 0, 0}, {0, 0, 0, 1, 1, 1}, {0, 3, 1, 1, 1, 1}}, false}, // Logica is taught on a B week
+	}
+
+	for _, scenario := range scenarios {
+		// Act
+		valid := verify(scenario.timetable, input)
+
+		// Assert
+		assert.Equal(t, scenario.valid, valid, "timetable = %v", scenario.timetable)
+	}
+}
+
+func TestVerifyAssignments(t *testing.T) {
+	// Arrange
+	input, err := processRawInput(rawModelInput{ // Luciano teaches Logica to CC-111 twice on two days with two periods each, once in Aula 2 at period 0 on day 1
+		Subjects:   []Subject{{Id: 0, Name: "Logica"}},
+		Professors: []Professor{{Id: 0, Name: "Luciano", Availability: [][]bool{{true, true}, {true, true}}}},
+		Classes:    []rawClass{{Id: 0, Name: "CC-111", Size: 30}},
+		Rooms:      []Room{{Id: 0, Name: "Aula 1", Capacity: 50}, {Id: 1, Name: "Aula 2", Capacity: 50}},
+		Entries: []rawEntry{
+			{Subject: 0, Professor: 0, Classes: []uint64{0}, Lessons: 2, Permissibility: [][]bool{{true, true}, {true, true}}, Rooms: []uint64{0, 1}},
+		},
+		Pinned:    []rawAssignment{{Classes: []uint64{0}, Day: 1, Period: 0, Room: lo.ToPtr(uint64(1))}},
+		Forbidden: []rawAssignment{{Classes: []uint64{0}, Day: 0, Period: 0, Room: lo.ToPtr(uint64(0))}}, // Aula 1 is off-limits at period 0 on day 0
+	})
+	assert.Nil(t, err)
+	scenarios := []struct {
+		timetable [][6]uint64
+		valid     bool
+	}{
+		{[][6]uint64{{0, 0, 0, 0, 0, 1}, {0, 1, 1, 0, 0, 1}}, true},
+		{[][6]uint64{{0, 0, 0, 0, 0, 1}, {0, 1, 1, 0, 0, 0}}, false}, // The pinned lesson is taught in another room
+		{[][6]uint64{{0, 0, 0, 0, 0, 1}, {1, 1, 1, 0, 0, 1}}, false}, // No lesson is taught at the pinned slot
+		{[][6]uint64{{0, 0, 0, 0, 0, 0}, {0, 1, 1, 0, 0, 1}}, false}, // A lesson is taught at the forbidden slot and room
+	}
+
+	for _, scenario := range scenarios {
+		// Act
+		valid := verify(scenario.timetable, input)
+
+		// Assert
+		assert.Equal(t, scenario.valid, valid, "timetable = %v", scenario.timetable)
+	}
+}
+
+func TestVerifySimultaneous(t *testing.T) {
+	// Arrange
+	input, err := processRawInput(simultaneousRawInput(2))
+	assert.Nil(t, err)
+	scenarios := []struct {
+		timetable [][6]uint64
+		valid     bool
+	}{
+		{[][6]uint64{{0, 0, 0, 0, 0, 0}, {1, 1, 1, 0, 0, 0}, {0, 0, 1, 1, 1, 1}, {1, 1, 0, 1, 1, 1}}, true}, // Lessons may be paired in any order
+		{[][6]uint64{{0, 0, 0, 0, 0, 0}, {1, 1, 1, 0, 0, 0}, {0, 0, 0, 1, 1, 1}, {0, 1, 1, 1, 1, 1}}, false},
+	}
+
+	for _, scenario := range scenarios {
+		// Act
+		valid := verify(scenario.timetable, input)
+
+		// Assert
+		assert.Equal(t, scenario.valid, valid, "timetable = %v", scenario.timetable)
+	}
+}
+
+func TestVerifyTravel(t *testing.T) {
+	scenarios := []struct {
+		travelTimes bool
+		breaks      []uint64
+		timetable   [][6]uint64
+		valid       bool
+	}{
+		{false, nil, [][6]uint64{{0, 0, 0, 0, 0, 0}, {1, 0, 0, 1, 1, 1}}, true},
+		{true, []uint64{15}, [][6]uint64{{0, 0, 0, 0, 0, 0}, {1, 0, 0, 1, 1, 1}}, true},
+		{true, []uint64{5}, [][6]uint64{{0, 0, 0, 0, 0, 0}, {1, 0, 0, 1, 1, 1}}, false},
+		{true, []uint64{5}, [][6]uint64{{1, 0, 0, 0, 0, 0}, {0, 0, 0, 1, 1, 1}}, false},
+	}
+
+	for _, scenario := range scenarios {
+		// Arrange
+		input := travelInput(t, scenario.travelTimes, scenario.breaks)
+
+		// Act
+		valid := verify(scenario.timetable, input)
+
+		// Assert
+		assert.Equal(t, scenario.valid, valid, "travelTimes = %v, breaks = %v, timetable = %v", scenario.travelTimes, scenario.breaks, scenario.timetable)
+	}
+}
+
+func TestIsolatedRoomTravelTimes(t *testing.T) {
+	// Arrange
+	input := travelInput(t, true, []uint64{15})
+	timetabler := NewIsolatedRoomTimetabler(dpllSolver{}, false, 0)
+
+	// Act
+	_, _, _, buildErr := timetabler.Build(input)
+	_, enumerateErr := Enumerate(timetabler, input, 2, 1)
+	timetable, _, _, embeddedErr := NewEmbeddedRoomTimetabler(dpllSolver{}).Build(input)
+
+	// Assert
+	assert.Equal(t, errTravelTimes, buildErr)
+	assert.Equal(t, errTravelTimes, enumerateErr) // Enumeration solves the encoding without Build
+	assert.Nil(t, embeddedErr)
+	assert.True(t, verify(timetable, input))
+}
+
+func TestVerifyRoomStability(t *testing.T) {
+	scenarios := []struct {
+		stability string
+		timetable [][6]uint64
+		valid     bool
+	}{
+		{"entry", [][6]uint64{{0, 0, 0, 0, 0, 0}, {0, 1, 1, 0, 0, 0}, {1, 0, 0, 1, 0, 1}}, true},
+		{"entry", [][6]uint64{{0, 0, 0, 0, 0, 0}, {0, 1, 1, 0, 0, 1}, {1, 0, 0, 1, 0, 1}}, false}, // Logica is split across rooms
+		{"day", [][6]uint64{{0, 0, 0, 0, 0, 0}, {0, 1, 1, 0, 0, 1}, {1, 0, 0, 1, 0, 0}}, true},
+		{"day", [][6]uint64{{0, 0, 0, 0, 0, 0}, {0, 1, 1, 0, 0, 0}, {1, 0, 0, 1, 0, 1}}, false}, // CC-111 changes rooms on Monday
+	}
+
+	for _, scenario := range scenarios {
+		// Arrange
+		input := roomStabilityInput(t, scenario.stability, false)
+
+		// Act
+		valid := verify(scenario.timetable, input)
+
+		// Assert
+		assert.Equal(t, scenario.valid, valid, "stability = %v, timetable = %v", scenario.stability, scenario.timetable)
+	}
+}
+
+func TestVerifyTeachingDays(t *testing.T) {
+	scenarios := []struct {
+		maxTeachingDays uint64
+		timetable       [][6]uint64
+		valid           bool
+	}{
+		{1, [][6]uint64{{0, 0, 0, 0, 0, 0}, {1, 0, 0, 1, 1, 0}}, true},
+		{1, [][6]uint64{{0, 0, 0, 0, 0, 0}, {0, 1, 0, 1, 1, 0}}, false},
+		{2, [][6]uint64{{0, 0, 0, 0, 0, 0}, {0, 1, 0, 1, 1, 0}}, true},
+		{0, [][6]uint64{{0, 0, 0, 0, 0, 0}, {0, 1, 0, 1, 1, 0}}, true}, // No limit
+	}
+
+	for _, scenario := range scenarios {
+		// Arrange
+		input := teachingDaysInput(t, scenario.maxTeachingDays)
+
+		// Act
+		valid := verify(scenario.timetable, input)
+
+		// Assert
+		assert.Equal(t, scenario.valid, valid, "maxTeachingDays = %d, timetable = %v", scenario.maxTeachingDays, scenario.timetable)
+	}
+}
+
+// SAT solver that blocks until its context is done, signaling its cancellation
+type blockingSolver struct {
+	cancelled chan struct{}
+}
+
+func (solver *blockingSolver) Solve(instance sat.SAT) (sat.SATSolution, error) {
+	return solver.SolveContext(context.Background(), instance)
+}
+
+func (solver *blockingSolver) SolveContext(ctx context.Context, _ sat.SAT) (sat.SATSolution, error) {
+	<-ctx.Done()
+	close(solver.cancelled)
+	return nil, ctx.Err()
+}
+
+func TestIterativeBudgetCancelsSolver(t *testing.T) {
+	// Arrange
+	input, err := processRawInput(rawModelInput{ // Luciano teaches Logica to CC-111 once, in a single slot
+		Subjects:   []Subject{{Id: 0, Name: "Logica"}},
+		Professors: []Professor{{Id: 0, Name: "Luciano", Availability: [][]bool{{true}}}},
+		Classes:    []rawClass{{Id: 0, Name: "CC-111", Size: 30}},
+		Rooms:      []Room{{Id: 0, Name: "Aula 1", Capacity: 50}},
+		Entries:    []rawEntry{{Subject: 0, Professor: 0, Classes: []uint64{0}, Lessons: 1, Permissibility: [][]bool{{true}}, Rooms: []uint64{0}}},
+	})
+	assert.Nil(t, err)
+	solver := &blockingSolver{cancelled: make(chan struct{})}
+	timetabler := NewIterativeTimetabler(solver, ObjectiveGaps, LinearSearch, 10*time.Millisecond)
+
+	// Act
+	timetable, _, _, err := timetabler.Build(input)
+
+	// Assert
+	assert.NotNil(t, err)
+	assert.Nil(t, timetable)
+	select {
+	case <-solver.cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("the solver kept running after the budget expired")
+	}
+}
+
+// SAT solver that solves the first instance it's given and blocks on the next ones until their context is done, signaling its cancellation
+type stallingSolver struct {
+	blockingSolver
+	solved bool
+}
+
+func (solver *stallingSolver) Solve(instance sat.SAT) (sat.SATSolution, error) {
+	return solver.SolveContext(context.Background(), instance)
+}
+
+func (solver *stallingSolver) SolveContext(ctx context.Context, instance sat.SAT) (sat.SATSolution, error) {
+	if !solver.solved {
+		solver.solved = true
+		return dpllSolver{}.Solve(instance)
+	}
+	return solver.blockingSolver.SolveContext(ctx, instance)
+}
+
+func TestIterativeBudgetReturnsBestTimetable(t *testing.T) {
+	// Arrange
+	input := iterativeInput(t)
+	solver := &stallingSolver{blockingSolver: blockingSolver{cancelled: make(chan struct{})}}
+	timetabler := NewIterativeTimetabler(solver, ObjectiveLatePeriods, LinearSearch, 50*time.Millisecond)
+
+	// Act
+	timetable, cost, err := timetabler.Optimize(input)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.True(t, verify(timetable, input))
+	assert.Greater(t, cost.Total(), uint64(1)) // The first timetable is returned unimproved, since tightening its bound stalls until the budget expires
+	select {
+	case <-solver.cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("the solver kept running after the budget expired")
+	}
+}
+
+func TestIterativeSearch(t *testing.T) {
+	scenarios := []struct {
+		search    Search
+		objective Objective
+		minimum   uint64
+	}{
+		{LinearSearch, ObjectiveLatePeriods, 1},
+		{BinarySearch, ObjectiveLatePeriods, 1},
+		{LinearSearch, ObjectiveGaps, 0},
+		{BinarySearch, ObjectiveGaps, 0},
+	}
+
+	for _, scenario := range scenarios {
+		// Arrange
+		input := iterativeInput(t)
+		timetabler := NewIterativeTimetabler(dpllSolver{}, scenario.objective, scenario.search, 0)
+
+		// Act
+		timetable, cost, err := timetabler.Optimize(input)
+
+		// Assert
+		assert.Nil(t, err)
+		assert.True(t, verify(timetable, input))
+		assert.Equal(t, scenario.minimum, cost.Total(), "search = %v, objective = %v", scenario.search, scenario.objective)
+	}
+}
+
+// Returns an input where Luciano teaches Logica and Dalianys teaches Algebra to CC-111 twice each, and Celia teaches it Fisica once, in Aula 1 on
+// two days with three periods each. The five lessons take all but one slot, so at least one of them is taught at the last period
+func iterativeInput(t *testing.T) ModelInput {
+	availability := func() [][]bool { return [][]bool{{true, true}, {true, true}, {true, true}} }
+	input, err := processRawInput(rawModelInput{
+		Subjects: []Subject{{Id: 0, Name: "Logica"}, {Id: 1, Name: "Algebra"}, {Id: 2, Name: "Fisica"}},
+		Professors: []Professor{
+			{Id: 0, Name: "Luciano", Availability: availability()},
+			{Id: 1, Name: "Dalianys", Availability: availability()},
+			{Id: 2, Name: "Celia", Availability: availability()},
+		},
+		Classes: []rawClass{{Id: 0, Name: "CC-111", Size: 30}},
+		Rooms:   []Room{{Id: 0, Name: "Aula 1", Capacity: 50}},
+		Entries: []rawEntry{
+			{Subject: 0, Professor: 0, Classes: []uint64{0}, Lessons: 2, Permissibility: availability(), Rooms: []uint64{0}},
+			{Subject: 1, Professor: 1, Classes: []uint64{0}, Lessons: 2, Permissibility: availability(), Rooms: []uint64{0}},
+			{Subject: 2, Professor: 2, Classes: []uint64{0}, Lessons: 1, Permissibility: availability(), Rooms: []uint64{0}},
+		},
+	})
+	assert.Nil(t, err)
+	return input
+}
+
+// DPLL SAT solver with unit propagation, enough for the tiny instances of the tests that need actual solutions
+type dpllSolver struct{}
+
+func (solver dpllSolver) Solve(instance sat.SAT) (sat.SATSolution, error) {
+	assignment := make([]int8, instance.Variables+1) // 1 for true, -1 for false and 0 for unassigned
+	if !dpll(instance.Clauses, assignment) {
+		return nil, nil
+	}
+
+	solution := make(sat.SATSolution, instance.Variables)
+	for variable := range int64(instance.Variables) {
+		solution[variable] = variable + 1
+		if assignment[variable+1] < 0 {
+			solution[variable] = -solution[variable]
+		}
+	}
+	return solution, nil
+}
+
+// Extends the assignment to satisfy every clause, leaving it untouched if unsatisfiable
+func dpll(clauses [][]int64, assignment []int8) bool {
+	abs := func(literal int64) int64 { return max(literal, -literal) }
+	value := func(literal int64) int8 {
+		if literal > 0 {
+			return assignment[literal]
+		}
+		return -assignment[-literal]
+	}
+	assign := func(literal int64) {
+		assignment[abs(literal)] = 1
+		if literal < 0 {
+			assignment[abs(literal)] = -1
+		}
+	}
+
+	propagated := make([]int64, 0)
+	undo := func() {
+		for _, variable := range propagated {
+			assignment[variable] = 0
+		}
+	}
+
+	for {
+		progress, branch := false, int64(0)
+		for _, clause := range clauses {
+			satisfied, free, unassigned := false, 0, int64(0)
+			for _, literal := range clause {
+				if literalValue := value(literal); literalValue > 0 {
+					satisfied = true
+					break
+				} else if literalValue == 0 {
+					free, unassigned = free+1, literal
+				}
+			}
+
+			if satisfied {
+				continue
+			} else if free == 0 {
+				undo()
+				return false
+			} else if free == 1 {
+				assign(unassigned)
+				propagated, progress = append(propagated, abs(unassigned)), true
+			} else if branch == 0 {
+				branch = unassigned
+			}
+		}
+		if progress {
+			continue
+		} else if branch == 0 {
+			return true
+		}
+
+		for _, literal := range []int64{branch, -branch} {
+			assign(literal)
+			if dpll(clauses, assignment) {
+				return true
+			}
+			assignment[abs(literal)] = 0
+		}
+		undo()
+		return false
+	}
+}
+
+// MaxSAT solver branching on whether each soft clause is satisfied (i.e. added to the hard clauses) or falsified (i.e. its weight is paid), which
+// prunes the branches whose hard clauses are unsatisfiable or whose cost cannot improve the best one found so far. It's only meant for small instances
+type branchAndBoundSolver struct{}
+
+func (solver branchAndBoundSolver) Solve(instance sat.MaxSAT) (sat.SATSolution, uint64, error) {
+	var (
+		best     sat.SATSolution
+		bestCost uint64
+		search   func(hard [][]int64, soft []sat.WeightedClause, cost uint64)
+	)
+	search = func(hard [][]int64, soft []sat.WeightedClause, cost uint64) {
+		if best != nil && cost >= bestCost {
+			return
+		}
+		solution, _ := dpllSolver{}.Solve(sat.SAT{Variables: instance.Variables, Clauses: hard})
+		if solution == nil {
+			return
+		}
+
+		// The solution bounds the cost of the branch
+		trueLiterals := lo.SliceToMap(solution, func(literal int64) (int64, bool) { return literal, true })
+		solutionCost := cost
+		for _, clause := range soft {
+			if !lo.SomeBy(clause.Literals, func(literal int64) bool { return trueLiterals[literal] }) {
+				solutionCost += clause.Weight
+			}
+		}
+		if best == nil || solutionCost < bestCost {
+			best, bestCost = solution, solutionCost
+		}
+		if len(soft) == 0 || solutionCost == cost {
+			return
+		}
+
+		search(append(slices.Clip(hard), soft[0].Literals), soft[1:], cost)
+		search(hard, soft[1:], cost+soft[0].Weight)
+	}
+
+	soft := slices.SortedStableFunc(slices.Values(instance.Soft), func(a, b sat.WeightedClause) int { return cmp.Compare(b.Weight, a.Weight) }) // Heavier clauses prune more
+	search(instance.Hard, soft, 0)
+	return best, bestCost, nil
+}
+
+// Returns a raw input where Luciano teaches Logica to CC-111 in Aula 1 at the same time Dalianys teaches Algebra to CC-112 in Aula 2, the given
+// number of lessons each on two days with two periods each
+func simultaneousRawInput(lessons uint64) rawModelInput {
+	availability := func() [][]bool { return [][]bool{{true, true}, {true, true}} }
+	return rawModelInput{
+		Subjects: []Subject{{Id: 0, Name: "Logica"}, {Id: 1, Name: "Algebra"}},
+		Professors: []Professor{
+			{Id: 0, Name: "Luciano", Availability: availability()},
+			{Id: 1, Name: "Dalianys", Availability: availability()},
+		},
+		Classes: []rawClass{{Id: 0, Name: "CC-111", Size: 30}, {Id: 1, Name: "CC-112", Size: 30}},
+		Rooms:   []Room{{Id: 0, Name: "Aula 1", Capacity: 50}, {Id: 1, Name: "Aula 2", Capacity: 50}},
+		Entries: []rawEntry{
+			{Subject: 0, Professor: 0, Classes: []uint64{0}, Lessons: lessons, Permissibility: availability(), Rooms: []uint64{0}},
+			{Subject: 1, Professor: 1, Classes: []uint64{1}, Lessons: lessons, Permissibility: availability(), Rooms: []uint64{1}},
+		},
+		Simultaneous: [][]rawEntryReference{{{Subject: 0, Professor: 0, Classes: []uint64{0}}, {Subject: 1, Professor: 1, Classes: []uint64{1}}}},
+	}
+}
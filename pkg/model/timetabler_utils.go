@@ -1,18 +1,32 @@
 package model
 
 import (
-	"fmt"
 	"log"
 	"slices"
-	"strings"
 
 	"github.com/limaJavier/timetabling/pkg/sat"
 
 	"github.com/onsi/gomega/matchers/support/goraph/bipartitegraph"
+	"github.com/onsi/gomega/matchers/support/goraph/edge"
 	"github.com/samber/lo"
 )
 
+const (
+	roomPreferenceCost = 10  // Cost of each position a room falls behind in the entry's preference order, worth as many empty seats
+	roomStabilityCost  = 100 // Cost of teaching a lesson in another room than the one of the entry's earliest lesson, worth as many empty seats
+
+	requestedRoomStabilityCost = 1000 // Cost of teaching a lesson in another room than the earliest one within its soft room-stability scope
+)
+
+// Room a hard room-stability scope is bound to, along with the stable variable binding it
+type boundRoom struct {
+	room     uint64
+	variable int64
+}
+
+// Error returned when some lessons taught at the same period and day cannot be assigned a room each
 type unassignableError struct {
+	violators [][]int64 // Sets of variables that cannot be true simultaneously, since their lessons fit in fewer rooms than lessons (i.e. they violate Hall's condition)
 }
 
 func (err unassignableError) Error() string {
@@ -62,32 +76,38 @@ func verify(timetable [][6]uint64, modelInput ModelInput) bool {
 	lessonTaught := make(map[[3]uint64]bool)
 
 	for _, positive := range timetable {
-		period, day, subjectProfessor, group, room := positive[0], positive[1], positive[3], positive[4], positive[5]
-		professor := modelInput.SubjectProfessors[subjectProfessor].Professor
+		period, day, lesson, subjectProfessor, group, room := positive[0], positive[1], positive[2], positive[3], positive[4], positive[5]
+		team := modelInput.SubjectProfessors[subjectProfessor].Team()
 		entryKey := [2]uint64{subjectProfessor, group}
 
 		_, alreadyTaught := lessonTaught[[3]uint64{group, subjectProfessor, day}]
 		// Check that:
 		// - SubjectProfessor is allowed to teach (or to be taught) in the period and day
-		// - Professor is available in the period and day
-		// - Professor is not already assisting in the period and day
+		// - Every professor of the team is available in the period and day
+		// - No professor of the team is already assisting in the period and day
 		// - A group with a common class is not already scheduled in the period and day (no collision)
 		// - A subjectProfessor can only teach (or be taught to) a group once a day
 		// - Room is not assigned to subjectProfessor
 		// - Group does not fit in room
+		// - Room is not available in the period and day
 		// - Room must not be already assigned in the period and day
+		// - Room must comply with the pinned and forbidden assignments
 		if !modelInput.Entries[entryKey].Permissibility[period][day] ||
 			!evaluator.ProfessorAvailable(subjectProfessor, day, period) ||
-			professorAssistance[professor][period][day] ||
+			lo.SomeBy(team, func(professor uint64) bool { return professorAssistance[professor][period][day] }) ||
 			collide(modelInput.GroupsGraph, groupAssistance, group, period, day) ||
 			alreadyTaught ||
 			!evaluator.Assigned(room, subjectProfessor, group) ||
 			!evaluator.Fits(group, room) ||
-			roomAssistance[room][period][day] {
+			!evaluator.RoomAvailable(room, day, period) ||
+			roomAssistance[room][period][day] ||
+			!roomPermitted(modelInput, evaluator, period, day, lesson, subjectProfessor, group, room) {
 			return false
 		}
 
-		professorAssistance[professor][period][day] = true           // Store professor assistance
+		for _, professor := range team {
+			professorAssistance[professor][period][day] = true // Store professor assistance
+		}
 		groupAssistance[group][period][day] = true                   // Store group assistance
 		roomAssistance[room][period][day] = true                     // Store room assistance
 		derivedLessons[entryKey]++                                   // Store lesson taught
@@ -95,8 +115,119 @@ func verify(timetable [][6]uint64, modelInput ModelInput) bool {
 	}
 
 	// Check whether the number of lessons taught for each subjectProfessor is equal to the number of lessons assigned in the curriculum
+	// (candidate entries may be left untaught, as long as exactly one entry of each pool is taught)
+	for key, value := range modelInput.Entries {
+		if _, candidate := candidatePool(modelInput, key); derivedLessons[key] != value.Lessons && !(candidate && derivedLessons[key] == 0) {
+			return false
+		}
+	}
+	for _, pool := range modelInput.Pools {
+		if lo.CountBy(pool, func(entryKey [2]uint64) bool { return derivedLessons[entryKey] > 0 }) != 1 {
+			return false
+		}
+	}
+
+	// Check whether every taught entry teaches its weekly lessons each week
+	weeklyLessons := make(map[[3]uint64]uint64)
+	for _, positive := range timetable {
+		week, _ := WeekDay(modelInput, positive[1])
+		weeklyLessons[[3]uint64{positive[3], positive[4], week}]++
+	}
 	for key, value := range modelInput.Entries {
-		if derivedLessons[key] != value.Lessons {
+		for week := range uint64(len(value.WeeklyLessons)) {
+			if taught := weeklyLessons[[3]uint64{key[0], key[1], week}]; derivedLessons[key] > 0 && taught != value.WeeklyLessons[week] {
+				return false
+			}
+		}
+	}
+
+	// Check whether every professor's weekly load is within its bounds
+	load := make(map[[2]uint64]uint64)
+	for _, positive := range timetable {
+		week, _ := WeekDay(modelInput, positive[1])
+		for _, professor := range modelInput.SubjectProfessors[positive[3]].Team() {
+			load[[2]uint64{professor, week}]++
+		}
+	}
+	for professor, value := range modelInput.Professors {
+		for week := range max(modelInput.Weeks, 1) {
+			if load := load[[2]uint64{uint64(professor), week}]; load < value.MinLoad || (value.MaxLoad > 0 && load > value.MaxLoad) {
+				return false
+			}
+		}
+	}
+
+	// Check whether every pinned assignment is present in the timetable
+	for pinned, assignment := range modelInput.Pinned {
+		if !lo.SomeBy(timetable, func(positive [6]uint64) bool {
+			return positive[0] == assignment.Period &&
+				positive[1] == assignment.Day &&
+				positive[3] == assignment.SubjectProfessor &&
+				positive[4] == assignment.Group &&
+				evaluator.Pinned(uint64(pinned), positive[2], positive[5])
+		}) {
+			return false
+		}
+	}
+
+	// Check whether every professor teaches on at most MaxTeachingDays days per week
+	teachingDays := make(map[[2]uint64]map[uint64]bool)
+	for _, positive := range timetable {
+		week, _ := WeekDay(modelInput, positive[1])
+		for _, professor := range modelInput.SubjectProfessors[positive[3]].Team() {
+			key := [2]uint64{professor, week}
+			if _, ok := teachingDays[key]; !ok {
+				teachingDays[key] = make(map[uint64]bool)
+			}
+			teachingDays[key][positive[1]] = true
+		}
+	}
+	for key, days := range teachingDays {
+		if maxTeachingDays := modelInput.Professors[key[0]].MaxTeachingDays; maxTeachingDays > 0 && uint64(len(days)) > maxTeachingDays {
+			return false
+		}
+	}
+
+	// Check whether professors and classes can reach the room of their next lesson during the break
+	if len(modelInput.TravelTimes) > 0 {
+		for _, positive1 := range timetable {
+			for _, positive2 := range timetable {
+				if positive2[1] == positive1[1] && positive2[0] == positive1[0]+1 &&
+					(evaluator.SameProfessor(positive1[3], positive2[3]) || !evaluator.Disjoint(positive1[4], positive2[4])) &&
+					!evaluator.Reachable(positive1[5], positive2[5], positive1[0]) {
+					return false
+				}
+			}
+		}
+	}
+
+	// Check whether the lessons within every hard room-stability scope are taught in the same room
+	stableRooms := make(map[[3]uint64]uint64)
+	for _, positive := range timetable {
+		if modelInput.Entries[[2]uint64{positive[3], positive[4]}].SoftRoomStability {
+			continue
+		}
+		for _, scope := range roomStabilityScopes(modelInput, positive[3], positive[4], positive[1]) {
+			if room, ok := stableRooms[scope]; ok && room != positive[5] {
+				return false
+			}
+			stableRooms[scope] = positive[5]
+		}
+	}
+
+	// Check whether the entries of every simultaneous group are scheduled at identical periods and days
+	for _, entryKeys := range modelInput.Simultaneous {
+		slots := lo.Map(entryKeys, func(entryKey [2]uint64, _ int) [][2]uint64 {
+			entrySlots := make([][2]uint64, 0)
+			for _, positive := range timetable {
+				if positive[3] == entryKey[0] && positive[4] == entryKey[1] {
+					entrySlots = append(entrySlots, [2]uint64{positive[0], positive[1]})
+				}
+			}
+			slices.SortFunc(entrySlots, func(a, b [2]uint64) int { return slices.Compare(a[:], b[:]) })
+			return entrySlots
+		})
+		if lo.SomeBy(slots, func(entrySlots [][2]uint64) bool { return !slices.Equal(entrySlots, slots[0]) }) {
 			return false
 		}
 	}
@@ -124,8 +255,8 @@ func buildSat(variables uint64, constraints []func(state constraintState) [][]in
 	for clauses := range constraintsChannel {
 		for _, clause := range clauses {
 			for _, variable := range clause {
-				// Check whether the variable is positive, since required explicit variables ought to be positive
-				if variable > 0 {
+				// Check whether the variable is positive, since required explicit variables ought to be positive, and whether it's a scheduling variable (i.e. not an auxiliary one)
+				if variable > 0 && variable <= int64(variables) {
 					explicitVariables[variable] = true
 				}
 			}
@@ -139,14 +270,20 @@ func buildSat(variables uint64, constraints []func(state constraintState) [][]in
 		}
 	}
 
+	// Account for the auxiliary variables introduced by the constraints
+	satInstance.Variables = state.allocator.Last()
+
 	return satInstance, explicitVariables
 }
 
-func roomAssignment(solution sat.SATSolution, indexer indexer, evaluator predicateEvaluator, modelInput ModelInput) ([][6]uint64, error) {
+// Assigns rooms to the scheduling variables of the solution, where the lessons of entries with hard room stability are restricted to the rooms their
+// scopes are bound to. Returns an unassignableError if the lessons taught at some period and day cannot be assigned a room each
+func roomAssignment(solution sat.SATSolution, bound map[[3]uint64]boundRoom, indexer indexer, evaluator predicateEvaluator, modelInput ModelInput) ([][6]uint64, error) {
 	simultaneousVariables, simultaneousRooms, simultaneousRelationships := make(map[[2]uint64][]int64), make(map[[2]uint64][]uint64), make(map[[2]uint64]map[[2]uint64]bool)
+	reasons := make(map[int64][]int64) // Stable variables restricting the rooms of each variable
 
 	for _, variable := range solution {
-		period, day, _, subjectProfessor, group, _ := indexer.Attributes(uint64(variable))
+		period, day, lesson, subjectProfessor, group, _ := indexer.Attributes(uint64(variable))
 		key := [2]uint64{period, day}
 		entryKey := [2]uint64{subjectProfessor, group}
 
@@ -160,9 +297,26 @@ func roomAssignment(solution sat.SATSolution, indexer indexer, evaluator predica
 		// Add simultaneous variable
 		simultaneousVariables[key] = append(simultaneousVariables[key], variable)
 
+		// Find the rooms the variable's hard room-stability scopes are bound to
+		stableRooms := make([]uint64, 0)
+		if entry := modelInput.Entries[entryKey]; !entry.SoftRoomStability {
+			for _, scope := range roomStabilityScopes(modelInput, subjectProfessor, group, day) {
+				if boundRoom, ok := bound[scope]; ok {
+					stableRooms = append(stableRooms, boundRoom.room)
+					reasons[variable] = append(reasons[variable], boundRoom.variable)
+				}
+			}
+		}
+
 		for _, room := range modelInput.Entries[entryKey].Rooms {
-			// Add simultaneous room after verifying it fits the group
-			if !slices.Contains(simultaneousRooms[key], room) && evaluator.Fits(group, room) {
+			// Skip rooms that do not fit the group, are not available, do not comply with the pinned and forbidden assignments or differ from the bound ones
+			if !evaluator.Fits(group, room) || !evaluator.RoomAvailable(room, day, period) || !roomPermitted(modelInput, evaluator, period, day, lesson, subjectProfessor, group, room) ||
+				lo.SomeBy(stableRooms, func(stableRoom uint64) bool { return stableRoom != room }) {
+				continue
+			}
+
+			// Add simultaneous room
+			if !slices.Contains(simultaneousRooms[key], room) {
 				simultaneousRooms[key] = append(simultaneousRooms[key], room)
 			}
 
@@ -170,38 +324,62 @@ func roomAssignment(solution sat.SATSolution, indexer indexer, evaluator predica
 			if _, ok := simultaneousRelationships[key][pair]; ok {
 				log.Panicf("variable-room pair %v~%v must be added only once", variable, room)
 			}
-			// Add simultaneous relationship after verifying room fits group
-			if evaluator.Fits(group, room) {
-				simultaneousRelationships[key][pair] = true
+			// Add simultaneous relationship
+			simultaneousRelationships[key][pair] = true
+		}
+	}
+
+	// Rooms of the entries' earliest lessons (and of the earliest lessons within each soft room-stability scope), which the following lessons should keep
+	entryRooms, scopeRooms := make(map[[2]uint64]uint64), make(map[[3]uint64]uint64)
+	softScopes := func(day, subjectProfessor, group uint64) [][3]uint64 {
+		if !modelInput.Entries[[2]uint64{subjectProfessor, group}].SoftRoomStability {
+			return nil
+		}
+		return roomStabilityScopes(modelInput, subjectProfessor, group, day)
+	}
+	cost := func(variable int64, room uint64) int64 {
+		_, day, _, subjectProfessor, group, _ := indexer.Attributes(uint64(variable))
+		entryKey := [2]uint64{subjectProfessor, group}
+
+		cost := int64(modelInput.Rooms[room].Capacity - groupSize(modelInput, group))
+		cost += roomPreferenceCost * int64(slices.Index(modelInput.Entries[entryKey].Rooms, room))
+		if entryRoom, ok := entryRooms[entryKey]; ok && entryRoom != room {
+			cost += roomStabilityCost
+		}
+		for _, scope := range softScopes(day, subjectProfessor, group) {
+			if scopeRoom, ok := scopeRooms[scope]; ok && scopeRoom != room {
+				cost += requestedRoomStabilityCost
 			}
 		}
+		return cost
+	}
+
+	// Assign rooms chronologically, so that later lessons tend to keep the rooms of the earlier ones
+	keys := make([][2]uint64, 0, len(simultaneousVariables))
+	for key := range simultaneousVariables {
+		keys = append(keys, key)
 	}
+	slices.SortFunc(keys, compareSlots)
 
 	timetable := make([][6]uint64, 0, len(solution))
-	for key, variables := range simultaneousVariables {
+	violators := make([][]int64, 0)
+	for _, key := range keys {
+		variables := simultaneousVariables[key]
 		rooms := simultaneousRooms[key]
 		relationships := simultaneousRelationships[key]
 
-		assignments, err := assignRooms(variables, rooms, relationships)
-		if _, ok := err.(unassignableError); ok {
-			var builder strings.Builder
-			for _, variable := range variables {
-				_, _, _, subjectProfessor, group, _ := indexer.Attributes(uint64(variable))
-				entryKey := [2]uint64{subjectProfessor, group}
-
-				subject := modelInput.Subjects[modelInput.SubjectProfessors[subjectProfessor].Subject].Name
-				fmt.Fprintf(&builder, "\tsubject: %v -> { ", subject)
-
-				for _, room := range modelInput.Entries[entryKey].Rooms {
-					if evaluator.Fits(group, room) {
-						roomName := modelInput.Rooms[room].Name
-						fmt.Fprintf(&builder, "%v, ", roomName)
-					}
+		assignments, err := assignRooms(variables, rooms, relationships, cost)
+		if unassignable, ok := err.(unassignableError); ok {
+			// The violators cannot be true simultaneously only along with the stable variables restricting their rooms
+			for _, violator := range unassignable.violators {
+				for _, variable := range violator {
+					violator = append(violator, reasons[variable]...)
 				}
-				builder.WriteString("}\n")
+				slices.Sort(violator)
+				violators = append(violators, slices.Compact(violator))
 			}
-			log.Printf("cannot assign rooms: \n%v\t%v", builder.String(), err)
-			return nil, nil
+			// Keep checking the remaining periods and days to report every violation at once
+			continue
 		} else if err != nil {
 			return nil, err
 		}
@@ -214,13 +392,26 @@ func roomAssignment(solution sat.SATSolution, indexer indexer, evaluator predica
 			positive[0], positive[1], positive[2], positive[3], positive[4], _ = indexer.Attributes(variable)
 
 			timetable = append(timetable, positive)
+			if entryKey := [2]uint64{positive[3], positive[4]}; !lo.HasKey(entryRooms, entryKey) {
+				entryRooms[entryKey] = room
+			}
+			for _, scope := range softScopes(positive[1], positive[3], positive[4]) {
+				if !lo.HasKey(scopeRooms, scope) {
+					scopeRooms[scope] = room
+				}
+			}
 		}
 	}
 
+	if len(violators) > 0 {
+		return nil, unassignableError{violators: violators}
+	}
 	return timetable, nil
 }
 
-func assignRooms(variables []int64, rooms []uint64, relationships map[[2]uint64]bool) ([][2]uint64, error) {
+// Assigns a distinct related room to each variable minimizing the sum of the costs of the assignments, by means of the Hungarian algorithm.
+// Returns an unassignableError holding a Hall violator if there is no such assignment
+func assignRooms(variables []int64, rooms []uint64, relationships map[[2]uint64]bool, cost func(variable int64, room uint64) int64) ([][2]uint64, error) {
 	assignments := make([][2]uint64, 0, len(variables))
 
 	// Build neighbors predicate based on relationships
@@ -243,17 +434,109 @@ func assignRooms(variables []int64, rooms []uint64, relationships map[[2]uint64]
 
 	// Check the matching is a maximum one
 	if len(matching) < len(variables) {
-		return nil, unassignableError{}
+		return nil, unassignableError{violators: [][]int64{hallViolator(variables, rooms, relationships, matching)}}
+	}
+
+	// Unrelated pairs cost more than any assignment of related ones, so they're never chosen since a perfect matching exists
+	costs, unrelatedCost := make([][]int64, len(variables)), int64(1)
+	for i, variable := range variables {
+		costs[i] = make([]int64, len(rooms))
+		for j, room := range rooms {
+			if relationships[[2]uint64{uint64(variable), room}] {
+				costs[i][j] = cost(variable, room)
+				unrelatedCost += costs[i][j]
+			}
+		}
+	}
+	for i, variable := range variables {
+		for j, room := range rooms {
+			if !relationships[[2]uint64{uint64(variable), room}] {
+				costs[i][j] = unrelatedCost
+			}
+		}
 	}
 
+	for variableIndex, roomIndex := range hungarian(costs) {
+		assignments = append(assignments, [2]uint64{uint64(variables[variableIndex]), rooms[roomIndex]})
+	}
+
+	return assignments, nil
+}
+
+// Returns a set of variables whose rooms are fewer than them, given a maximum matching that leaves some variable unmatched. The set holds the
+// variables reachable from an unmatched one through alternating paths (i.e. any edge from a variable to a room and the matching edge back), whose
+// rooms are all matched to other variables of the set
+func hallViolator(variables []int64, rooms []uint64, relationships map[[2]uint64]bool, matching edge.EdgeSet) []int64 {
+	matchedVariable, matchedRoom := make(map[int]int), make(map[int]int) // Variable (room) index matched to each room (variable) index
 	for _, edge := range matching {
 		variableIndex, roomIndex := edge.Node1, edge.Node2-len(variables)
-		variable, room := variables[variableIndex], rooms[roomIndex]
+		matchedVariable[roomIndex], matchedRoom[variableIndex] = variableIndex, roomIndex
+	}
 
-		assignments = append(assignments, [2]uint64{uint64(variable), room})
+	queue := make([]int, 0, len(variables))
+	for variableIndex := range variables {
+		if _, ok := matchedRoom[variableIndex]; !ok {
+			queue = append(queue, variableIndex)
+			break
+		}
 	}
 
-	return assignments, nil
+	visitedRooms := make(map[int]bool)
+	violator := make([]int64, 0)
+	for len(queue) > 0 {
+		variableIndex := queue[0]
+		queue = queue[1:]
+		violator = append(violator, variables[variableIndex])
+
+		for roomIndex, room := range rooms {
+			if visitedRooms[roomIndex] || !relationships[[2]uint64{uint64(variables[variableIndex]), room}] {
+				continue
+			}
+			visitedRooms[roomIndex] = true
+			// Every reachable room is matched, otherwise the matching would not be a maximum one
+			queue = append(queue, matchedVariable[roomIndex])
+		}
+	}
+
+	slices.Sort(violator)
+	return violator
+}
+
+// Checks whether teaching the lesson in the room at the given period and day complies with the pinned and forbidden assignments
+func roomPermitted(modelInput ModelInput, evaluator predicateEvaluator, period, day, lesson, subjectProfessor, group, room uint64) bool {
+	applies := func(assignment Assignment) bool {
+		return assignment.Period == period && assignment.Day == day && assignment.SubjectProfessor == subjectProfessor && assignment.Group == group
+	}
+
+	for pinned, assignment := range modelInput.Pinned {
+		// A lesson pinned to the period and day must be taught in the pinned room
+		if applies(assignment) && (assignment.Lesson == Any || assignment.Lesson == lesson) && !evaluator.Pinned(uint64(pinned), lesson, room) {
+			return false
+		}
+	}
+
+	for forbidden, assignment := range modelInput.Forbidden {
+		if applies(assignment) && evaluator.Forbidden(uint64(forbidden), lesson, room) {
+			return false
+		}
+	}
+	return true
+}
+
+// Returns the scopes within which the entry's lesson at the given day must share its room, identified by the entry itself ({RoomStabilityEntry,
+// subjectProfessor, group}) or by each of its classes on that day ({RoomStabilityDay, class, day})
+func roomStabilityScopes(modelInput ModelInput, subjectProfessor, group, day uint64) [][3]uint64 {
+	switch modelInput.Entries[[2]uint64{subjectProfessor, group}].RoomStability {
+	case RoomStabilityEntry:
+		return [][3]uint64{{uint64(RoomStabilityEntry), subjectProfessor, group}}
+	case RoomStabilityDay:
+		return lo.Map(modelInput.Groups[group].Classes, func(class uint64, _ int) [3]uint64 { return [3]uint64{uint64(RoomStabilityDay), class, day} })
+	}
+	return nil
+}
+
+func groupSize(modelInput ModelInput, group uint64) uint64 {
+	return lo.Sum(lo.Map(modelInput.Groups[group].Classes, func(class uint64, _ int) uint64 { return modelInput.Classes[class].Size }))
 }
 
 func getAttributes(modelInput ModelInput) (periods, days, lessons, subjectProfessors, groups, rooms uint64) {
@@ -0,0 +1,46 @@
+package sat
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/samber/lo"
+)
+
+type WeightedClause struct {
+	Weight   uint64
+	Literals []int64
+}
+
+// MaxSAT represents a (weighted partial) MaxSAT instance, where hard clauses must be satisfied and the sum of the weights of the falsified soft clauses must be minimized
+type MaxSAT struct {
+	Variables uint64
+	Hard      [][]int64
+	Soft      []WeightedClause
+}
+
+// Returns the weight given to hard clauses, which is greater than the sum of all soft clauses' weights
+func (s MaxSAT) Top() uint64 {
+	return lo.SumBy(s.Soft, func(clause WeightedClause) uint64 { return clause.Weight }) + 1
+}
+
+func (s MaxSAT) ToWCNF() string {
+	var builder strings.Builder
+	top := s.Top()
+	fmt.Fprintf(&builder, "p wcnf %d %d %d\n", s.Variables, len(s.Hard)+len(s.Soft), top)
+	for _, clause := range s.Hard {
+		fmt.Fprintf(&builder, "%d ", top)
+		for _, literal := range clause {
+			fmt.Fprintf(&builder, "%d ", literal)
+		}
+		builder.WriteString("0\n")
+	}
+	for _, clause := range s.Soft {
+		fmt.Fprintf(&builder, "%d ", clause.Weight)
+		for _, literal := range clause.Literals {
+			fmt.Fprintf(&builder, "%d ", literal)
+		}
+		builder.WriteString("0\n")
+	}
+	return builder.String()
+}
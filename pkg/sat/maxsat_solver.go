@@ -0,0 +1,5 @@
+package sat
+
+type MaxSATSolver interface {
+	Solve(MaxSAT) (SATSolution, uint64, error) // Returns an optimal solution of the MaxSAT instance along with its cost if the hard clauses are satisfiable, else returns nil (these are valid outputs where error shall be nil)
+}
@@ -0,0 +1,51 @@
+package sat
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToWCNF(t *testing.T) {
+	maxsat := MaxSAT{
+		Variables: 2,
+		Hard:      [][]int64{{1, 2}},
+		Soft:      []WeightedClause{{Weight: 2, Literals: []int64{-1}}, {Weight: 3, Literals: []int64{-2}}},
+	}
+
+	assert.Equal(t, "p wcnf 2 3 6\n6 1 2 0\n2 -1 0\n3 -2 0\n", maxsat.ToWCNF())
+}
+
+func TestParseMaxSATSolution(t *testing.T) {
+	scenarios := []struct {
+		output   string
+		format   modelFormat
+		solution SATSolution // Nil if the hard clauses are unsatisfiable
+		cost     uint64
+		valid    bool
+	}{
+		{"c comment\no 5\no 2\ns OPTIMUM FOUND\nv 1 -2 3\nv -4 0\n", literalModel, SATSolution{1, -2, 3, -4}, 2, true},
+		{"o 3\ns SATISFIABLE\nv 1 -2 0\n", literalModel, SATSolution{1, -2}, 3, true}, // Model found without proving its optimality
+		{"o 0\ns OPTIMUM FOUND\nv 10 0\n", literalModel, SATSolution{10}, 0, true},
+		{"o 1\ns OPTIMUM FOUND\nv 1001\n", bitStringModel, SATSolution{1, -2, -3, 4}, 1, true},
+		{"o 0\ns OPTIMUM FOUND\nv 10\n", bitStringModel, SATSolution{1, -2}, 0, true},
+		{"o 1\ns SATISFIABLE\nv 0\n", bitStringModel, SATSolution{-1}, 1, true},
+		{"s UNSATISFIABLE\n", literalModel, nil, 0, true},
+		{"s UNKNOWN\n", literalModel, nil, 0, false},
+		{"s OPTIMUM FOUND\nv 1 -2 0\n", bitStringModel, nil, 0, false},
+	}
+
+	for _, scenario := range scenarios {
+		// Act
+		solution, cost, err := parseMaxSATSolution(scenario.output, scenario.format)
+
+		// Assert
+		if !scenario.valid {
+			assert.NotNil(t, err, "output = %q", scenario.output)
+			continue
+		}
+		assert.Nil(t, err)
+		assert.Equal(t, scenario.solution, solution, "output = %q", scenario.output)
+		assert.Equal(t, scenario.cost, cost, "output = %q", scenario.output)
+	}
+}
@@ -1,5 +1,13 @@
 package sat
 
+import "context"
+
 type SATSolver interface {
 	Solve(SAT) (SATSolution, error) // Returns a solution of the SAT instance if satisfiable, else returns nil (these are valid outputs where error shall be nil)
 }
+
+// SAT solver whose execution can be cancelled
+type ContextSATSolver interface {
+	SATSolver
+	SolveContext(context.Context, SAT) (SATSolution, error) // Same as Solve, but kills the solver and returns the context's error once the context is done
+}
@@ -2,6 +2,7 @@ package sat
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"os/exec"
 	"strings"
@@ -14,10 +15,14 @@ func NewCryptominisatSolver() SATSolver {
 }
 
 func (solver *cryptominisatSolver) Solve(sat SAT) (SATSolution, error) {
+	return solver.SolveContext(context.Background(), sat)
+}
+
+func (solver *cryptominisatSolver) SolveContext(ctx context.Context, sat SAT) (SATSolution, error) {
 	cryptominisatPath := getExecutablePath("cryptominisatPath")
 	dimacs := sat.ToDIMACS() // Transform SAT into DIMACS-CNF string format
 
-	cmd := exec.Command(cryptominisatPath, "--verb", "0")
+	cmd := exec.CommandContext(ctx, cryptominisatPath, "--verb", "0")
 	cmd.Stdin = strings.NewReader(dimacs) // Feed dimacs into cryptominisat's standard input
 
 	var stdOut bytes.Buffer
@@ -26,6 +31,9 @@ func (solver *cryptominisatSolver) Solve(sat SAT) (SATSolution, error) {
 	cmd.Stderr = &stderr
 
 	err := cmd.Run()
+	if ctx.Err() != nil { // The solver was killed since the context is done
+		return nil, ctx.Err()
+	}
 	// Exit-code of 10 stands for satisfiable and exit-code 20 stands for unsatisfiable
 	if err != nil && cmd.ProcessState.ExitCode() != 10 && cmd.ProcessState.ExitCode() != 20 {
 		return nil, fmt.Errorf("an occurred during cryptominisat execution: %v : %v", err.Error(), stderr.String())
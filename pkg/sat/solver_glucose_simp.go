@@ -2,6 +2,7 @@ package sat
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"log"
@@ -20,6 +21,10 @@ func NewGlucoseSimpSolver() SATSolver {
 }
 
 func (solver *glucoseSimpSolver) Solve(sat SAT) (SATSolution, error) {
+	return solver.SolveContext(context.Background(), sat)
+}
+
+func (solver *glucoseSimpSolver) SolveContext(ctx context.Context, sat SAT) (SATSolution, error) {
 	glucoseSimpPath := getExecutablePath("glucoseSimpPath")
 	dimacs := sat.ToDIMACS() // Transform SAT into DIMACS-CNF string format
 
@@ -44,7 +49,7 @@ func (solver *glucoseSimpSolver) Solve(sat SAT) (SATSolution, error) {
 		return nil, fmt.Errorf("failed to close temporary file: %v", err)
 	}
 
-	cmd := exec.Command(glucoseSimpPath, "-verb=0")
+	cmd := exec.CommandContext(ctx, glucoseSimpPath, "-verb=0")
 	// Set the temporary file as the input for the command
 	cmd.Args = append(cmd.Args, inputTempFile.Name(), outputTempFile.Name())
 	cmd.Stdin = strings.NewReader(dimacs) // Feed dimacs into minisat's standard input
@@ -56,6 +61,9 @@ func (solver *glucoseSimpSolver) Solve(sat SAT) (SATSolution, error) {
 
 	// Exit-code of 10 stands for satisfiable and exit-code 20 stands for unsatisfiable
 	err = cmd.Run()
+	if ctx.Err() != nil { // The solver was killed since the context is done
+		return nil, ctx.Err()
+	}
 	if err != nil && cmd.ProcessState.ExitCode() != 10 && cmd.ProcessState.ExitCode() != 20 {
 		return nil, fmt.Errorf("an occurred during glucose-simp execution: %v : %v", err.Error(), stderr.String())
 	} else if cmd.ProcessState.ExitCode() == 20 {
@@ -2,6 +2,7 @@ package sat
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"os/exec"
 	"strings"
@@ -14,10 +15,14 @@ func NewKissatSolver() SATSolver {
 }
 
 func (solver *kissatSolver) Solve(sat SAT) (SATSolution, error) {
+	return solver.SolveContext(context.Background(), sat)
+}
+
+func (solver *kissatSolver) SolveContext(ctx context.Context, sat SAT) (SATSolution, error) {
 	kissatPath := getExecutablePath("kissatPath")
 	dimacs := sat.ToDIMACS() // Transform SAT into DIMACS-CNF string format
 
-	cmd := exec.Command(kissatPath, "-q", "--relaxed")
+	cmd := exec.CommandContext(ctx, kissatPath, "-q", "--relaxed")
 	cmd.Stdin = strings.NewReader(dimacs) // Feed dimacs into kissat's standard input
 
 	var stdOut bytes.Buffer
@@ -26,6 +31,9 @@ func (solver *kissatSolver) Solve(sat SAT) (SATSolution, error) {
 	cmd.Stderr = &stderr
 
 	err := cmd.Run()
+	if ctx.Err() != nil { // The solver was killed since the context is done
+		return nil, ctx.Err()
+	}
 	// Exit-code of 10 stands for satisfiable and exit-code 20 stands for unsatisfiable
 	if err != nil && cmd.ProcessState.ExitCode() != 10 && cmd.ProcessState.ExitCode() != 20 {
 		return nil, fmt.Errorf("an occurred during kissat execution: %v : %v", err.Error(), stderr.String())
@@ -0,0 +1,49 @@
+package sat
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+)
+
+type openWBOSolver struct{}
+
+func NewOpenWBOSolver() MaxSATSolver {
+	return &openWBOSolver{}
+}
+
+func (solver *openWBOSolver) Solve(maxsat MaxSAT) (SATSolution, uint64, error) {
+	openWBOPath := getExecutablePath("openwboPath")
+	wcnf := maxsat.ToWCNF() // Transform MaxSAT into WCNF string format
+
+	// Create a temporary file to hold the WCNF content
+	inputTempFile, err := os.CreateTemp("./", "wcnf-*.wcnf")
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to create temporary file: %v", err)
+	}
+	defer os.Remove(inputTempFile.Name()) // Ensure the file is removed after execution
+
+	// Write the WCNF content to the temporary file
+	if _, err := inputTempFile.WriteString(wcnf); err != nil {
+		return nil, 0, fmt.Errorf("failed to write WCNF to temporary file: %v", err)
+	}
+	if err := inputTempFile.Close(); err != nil {
+		return nil, 0, fmt.Errorf("failed to close temporary file: %v", err)
+	}
+
+	cmd := exec.Command(openWBOPath, "-verbosity=0", inputTempFile.Name())
+
+	var stdOut bytes.Buffer
+	cmd.Stdout = &stdOut
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	err = cmd.Run()
+	// Exit-code of 30 stands for optimum found, exit-code 20 stands for unsatisfiable and exit-code 10 stands for satisfiable (but not proven optimal)
+	if err != nil && cmd.ProcessState.ExitCode() != 10 && cmd.ProcessState.ExitCode() != 20 && cmd.ProcessState.ExitCode() != 30 {
+		return nil, 0, fmt.Errorf("an occurred during open-wbo execution: %v : %v", err.Error(), stderr.String())
+	}
+
+	return parseMaxSATSolution(stdOut.String(), bitStringModel)
+}
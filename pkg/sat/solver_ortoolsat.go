@@ -2,6 +2,7 @@ package sat
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
@@ -14,6 +15,10 @@ func NewOrtoolsatSolver() SATSolver {
 }
 
 func (solver *ortoolsatSolver) Solve(sat SAT) (SATSolution, error) {
+	return solver.SolveContext(context.Background(), sat)
+}
+
+func (solver *ortoolsatSolver) SolveContext(ctx context.Context, sat SAT) (SATSolution, error) {
 	ortoolsatPath := getExecutablePath("ortoolsatPath")
 	dimacs := sat.ToDIMACS() // Transform SAT into DIMACS-CNF string format
 
@@ -38,7 +43,7 @@ func (solver *ortoolsatSolver) Solve(sat SAT) (SATSolution, error) {
 		}
 	}()
 
-	cmd := exec.Command(ortoolsatPath)
+	cmd := exec.CommandContext(ctx, ortoolsatPath)
 	// Set the temporary file as the input for the command
 	cmd.Args = append(cmd.Args, tmpFile.Name())
 
@@ -49,6 +54,9 @@ func (solver *ortoolsatSolver) Solve(sat SAT) (SATSolution, error) {
 
 	// Exit-code of 10 stands for satisfiable and exit-code 20 stands for unsatisfiable
 	err = cmd.Run()
+	if ctx.Err() != nil { // The solver was killed since the context is done
+		return nil, ctx.Err()
+	}
 	if err != nil && cmd.ProcessState.ExitCode() != 10 && cmd.ProcessState.ExitCode() != 20 {
 		return nil, fmt.Errorf("an occurred during ortoolsat execution: %v : %v", err.Error(), stderr.String())
 	} else if cmd.ProcessState.ExitCode() == 20 {
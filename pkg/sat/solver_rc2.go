@@ -0,0 +1,47 @@
+package sat
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+)
+
+type rc2Solver struct{}
+
+func NewRC2Solver() MaxSATSolver {
+	return &rc2Solver{}
+}
+
+func (solver *rc2Solver) Solve(maxsat MaxSAT) (SATSolution, uint64, error) {
+	rc2Path := getExecutablePath("rc2Path")
+	wcnf := maxsat.ToWCNF() // Transform MaxSAT into WCNF string format
+
+	// Create a temporary file to hold the WCNF content
+	inputTempFile, err := os.CreateTemp("./", "wcnf-*.wcnf")
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to create temporary file: %v", err)
+	}
+	defer os.Remove(inputTempFile.Name()) // Ensure the file is removed after execution
+
+	// Write the WCNF content to the temporary file
+	if _, err := inputTempFile.WriteString(wcnf); err != nil {
+		return nil, 0, fmt.Errorf("failed to write WCNF to temporary file: %v", err)
+	}
+	if err := inputTempFile.Close(); err != nil {
+		return nil, 0, fmt.Errorf("failed to close temporary file: %v", err)
+	}
+
+	cmd := exec.Command(rc2Path, "-v", inputTempFile.Name())
+
+	var stdOut bytes.Buffer
+	cmd.Stdout = &stdOut
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, 0, fmt.Errorf("an occurred during rc2 execution: %v : %v", err.Error(), stderr.String())
+	}
+
+	return parseMaxSATSolution(stdOut.String(), literalModel)
+}
@@ -2,6 +2,7 @@ package sat
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
@@ -14,6 +15,10 @@ func NewSlimeSolver() SATSolver {
 }
 
 func (solver *slimeSolver) Solve(sat SAT) (SATSolution, error) {
+	return solver.SolveContext(context.Background(), sat)
+}
+
+func (solver *slimeSolver) SolveContext(ctx context.Context, sat SAT) (SATSolution, error) {
 	slimePath := getExecutablePath("slimePath")
 	dimacs := sat.ToDIMACS() // Transform SAT into DIMACS-CNF string format
 
@@ -38,7 +43,7 @@ func (solver *slimeSolver) Solve(sat SAT) (SATSolution, error) {
 		}
 	}()
 
-	cmd := exec.Command(slimePath)
+	cmd := exec.CommandContext(ctx, slimePath)
 	// Set the temporary file as the input for the command
 	cmd.Args = append(cmd.Args, tmpFile.Name())
 
@@ -49,6 +54,9 @@ func (solver *slimeSolver) Solve(sat SAT) (SATSolution, error) {
 
 	// Exit-code of 10 stands for satisfiable and exit-code 20 stands for unsatisfiable
 	err = cmd.Run()
+	if ctx.Err() != nil { // The solver was killed since the context is done
+		return nil, ctx.Err()
+	}
 	if err != nil && cmd.ProcessState.ExitCode() != 10 && cmd.ProcessState.ExitCode() != 20 {
 		return nil, fmt.Errorf("an occurred during slime execution: %v : %v", err.Error(), stderr.String())
 	} else if cmd.ProcessState.ExitCode() == 20 {
@@ -2,6 +2,7 @@ package sat
 
 import (
 	"encoding/json"
+	"fmt"
 	"log"
 	"os"
 	"strconv"
@@ -35,6 +36,74 @@ func parseSolution(solverOutput string) SATSolution {
 	return values[:len(values)-1]
 }
 
+// Format of the model line printed by a MaxSAT solver
+type modelFormat int
+
+const (
+	literalModel   modelFormat = iota // List of literals (e.g. "v 1 -2 3 0")
+	bitStringModel                    // String of 0s and 1s, one per variable (e.g. "v 101")
+)
+
+// Parses the standard MaxSAT-evaluation output, where "s" is the status line, "o" the cost line and "v" the model line (given in the solver's format)
+func parseMaxSATSolution(solverOutput string, format modelFormat) (SATSolution, uint64, error) {
+	var status string
+	var cost uint64
+	solution := SATSolution{}
+
+	for _, line := range strings.Split(solverOutput, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+
+		switch fields[0] {
+		case "s":
+			status = strings.Join(fields[1:], " ")
+		case "o":
+			value, err := strconv.ParseUint(fields[1], 10, 64)
+			if err != nil {
+				return nil, 0, fmt.Errorf("invalid cost in solver output: %v", err)
+			}
+			cost = value // Costs are improved along the execution, therefore the last one is kept
+		case "v":
+			if format == bitStringModel {
+				for _, field := range fields[1:] {
+					for _, value := range field {
+						switch value {
+						case '1':
+							solution = append(solution, int64(len(solution)+1))
+						case '0':
+							solution = append(solution, -int64(len(solution)+1))
+						default:
+							return nil, 0, fmt.Errorf("invalid value in solver output: %q", value)
+						}
+					}
+				}
+				continue
+			}
+
+			for _, valueStr := range fields[1:] {
+				value, err := strconv.ParseInt(valueStr, 10, 64)
+				if err != nil {
+					return nil, 0, fmt.Errorf("invalid literal in solver output: %v", err)
+				}
+				if value != 0 {
+					solution = append(solution, value)
+				}
+			}
+		}
+	}
+
+	switch status {
+	case "OPTIMUM FOUND", "SATISFIABLE": // Satisfiable stands for a model found without proving its optimality (e.g. after a timeout)
+		return solution, cost, nil
+	case "UNSATISFIABLE":
+		return nil, 0, nil
+	default:
+		return nil, 0, fmt.Errorf("unexpected status in solver output: \"%v\"", status)
+	}
+}
+
 func getExecutablePath(solver string) string {
 	bytes, _ := os.ReadFile(ConfigPath)
 	var inputJson map[string]any